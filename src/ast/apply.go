@@ -0,0 +1,80 @@
+package ast
+
+// An ApplyFunc is invoked by Apply for each node n before and/or after
+// the node's children, using a Cursor describing the current node and
+// providing operations on it. Nil children are skipped, so n is never nil
+// unless the root passed to Apply is.
+//
+// The return value of ApplyFunc controls the syntax tree traversal.
+// See Apply for details.
+type ApplyFunc func(*Cursor) bool
+
+// A Cursor describes a node encountered during Apply.
+type Cursor struct {
+	parent  Node
+	node    Node
+	replace func(Node)
+}
+
+// Node returns the current Node.
+func (c *Cursor) Node() Node { return c.node }
+
+// Parent returns the parent of the current Node, or nil for the root.
+func (c *Cursor) Parent() Node { return c.parent }
+
+// Replace replaces the current Node with n. A replacement made in pre is
+// walked by Apply in place of the original node: its children are
+// traversed and post is called for it. A replacement made in post is not
+// walked. It panics if n cannot be stored where the current node is, e.g.
+// a statement in place of an expression.
+func (c *Cursor) Replace(n Node) {
+	c.replace(n)
+	c.node = n
+}
+
+// Apply traverses a syntax tree recursively, starting with root, and
+// calling pre and post for each node as described below. Apply returns
+// the syntax tree, possibly modified.
+//
+// If pre is not nil, it is called for each node before the node's
+// children are traversed (pre-order). If pre replaces the node, the
+// children of the replacement are traversed instead. If pre returns false,
+// no children are traversed, and post is not called for that node.
+//
+// If post is not nil, and a prior call of pre didn't return false, post
+// is called for each node after its children are traversed (post-order).
+// If post returns false, traversal is terminated and Apply returns
+// immediately.
+func Apply(root Node, pre, post ApplyFunc) (result Node) {
+	result = root
+	a := &applier{pre: pre, post: post}
+	a.apply(nil, root, func(n Node) { result = n })
+	return result
+}
+
+type applier struct {
+	pre, post ApplyFunc
+	aborted   bool
+}
+
+func (a *applier) apply(parent, node Node, replace func(Node)) {
+	if a.aborted {
+		return
+	}
+
+	c := &Cursor{parent: parent, node: node, replace: replace}
+	if a.pre != nil && !a.pre(c) {
+		return
+	}
+
+	eachChild(c.node, func(child Node, replace func(Node)) {
+		a.apply(c.node, child, replace)
+	})
+
+	if a.aborted {
+		return
+	}
+	if a.post != nil && !a.post(c) {
+		a.aborted = true
+	}
+}
@@ -10,6 +10,7 @@ import (
 type ArrayLiteral struct {
 	Token    token.Token // the '[' token
 	Elements []Expression
+	Rbracket token.Position // zero for arrays not parsed from source
 }
 
 func (al *ArrayLiteral) expressionNode() {}
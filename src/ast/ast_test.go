@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
@@ -27,3 +29,311 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", program.String())
 	}
 }
+
+func TestModify(t *testing.T) {
+	one := func() Expression { return &IntegerLiteral{Value: 1} }
+	two := func() Expression { return &IntegerLiteral{Value: 2} }
+
+	turnOneIntoTwo := func(node Node) Node {
+		integer, ok := node.(*IntegerLiteral)
+		if !ok {
+			return node
+		}
+
+		if integer.Value != 1 {
+			return node
+		}
+
+		integer.Value = 2
+		return integer
+	}
+
+	tests := []struct {
+		input    Node
+		expected Node
+	}{
+		{one(), two()},
+		{
+			&Program{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			&Program{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+		},
+		{
+			&InfixExpression{Left: one(), Operator: "+", Right: two()},
+			&InfixExpression{Left: two(), Operator: "+", Right: two()},
+		},
+		{
+			&PrefixExpression{Operator: "-", Right: one()},
+			&PrefixExpression{Operator: "-", Right: two()},
+		},
+		{
+			&IndexExpression{Left: one(), Index: one()},
+			&IndexExpression{Left: two(), Index: two()},
+		},
+		{
+			&IfExpression{
+				Condition:   one(),
+				Consequence: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+				Alternative: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			},
+			&IfExpression{
+				Condition:   two(),
+				Consequence: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+				Alternative: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+			},
+		},
+		{
+			&ReturnStatement{ReturnValue: one()},
+			&ReturnStatement{ReturnValue: two()},
+		},
+		{
+			&LetStatement{Value: one()},
+			&LetStatement{Value: two()},
+		},
+		{
+			&FunctionLiteral{Parameters: []*Identifier{}, Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}}},
+			&FunctionLiteral{Parameters: []*Identifier{}, Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}}},
+		},
+		{
+			&ArrayLiteral{Elements: []Expression{one(), one()}},
+			&ArrayLiteral{Elements: []Expression{two(), two()}},
+		},
+		{
+			&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{one()}},
+			&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{two()}},
+		},
+		{
+			&ConditionalExpression{Condition: one(), Consequence: one(), Alternative: one()},
+			&ConditionalExpression{Condition: two(), Consequence: two(), Alternative: two()},
+		},
+		{
+			&RangeExpression{Start: one(), End: one(), Step: one()},
+			&RangeExpression{Start: two(), End: two(), Step: two()},
+		},
+		{
+			&MemberExpression{Left: &IndexExpression{Left: one(), Index: one()}, Member: &Identifier{Value: "x"}},
+			&MemberExpression{Left: &IndexExpression{Left: two(), Index: two()}, Member: &Identifier{Value: "x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		modified := Modify(tt.input, turnOneIntoTwo)
+
+		if !reflect.DeepEqual(modified, tt.expected) {
+			t.Errorf("not equal. got=%#v, want=%#v", modified, tt.expected)
+		}
+	}
+
+	hashLiteral := &HashLiteral{
+		Pairs: map[Expression]Expression{
+			one(): one(),
+			one(): one(),
+		},
+	}
+
+	Modify(hashLiteral, turnOneIntoTwo)
+
+	for key, val := range hashLiteral.Pairs {
+		key, _ := key.(*IntegerLiteral)
+		if key.Value != 2 {
+			t.Errorf("value is not %d, got=%d", 2, key.Value)
+		}
+		val, _ := val.(*IntegerLiteral)
+		if val.Value != 2 {
+			t.Errorf("value is not %d, got=%d", 2, val.Value)
+		}
+	}
+}
+
+func TestInspect(t *testing.T) {
+	ident := func(name string) *Identifier { return &Identifier{Value: name} }
+	integer := func(v int64) *IntegerLiteral { return &IntegerLiteral{Value: v} }
+
+	// let f = fn([a, ...rest]) { match a { 1 if rest => a + 2, _ => p.x } };
+	program := &Program{Statements: []Statement{
+		&LetStatement{
+			Name: ident("f"),
+			Value: &FunctionLiteral{
+				Parameters: []*Identifier{ident("[a, ...rest]")},
+				Patterns:   []Pattern{&ArrayPattern{Elements: []Pattern{&BindingPattern{Name: ident("a")}}, Rest: ident("rest")}},
+				Body: &BlockStatement{Statements: []Statement{
+					&ExpressionStatement{Expression: &MatchExpression{
+						Value: ident("a"),
+						Arms: []*MatchArm{
+							{
+								Pattern: &LiteralPattern{Value: integer(1)},
+								Guard:   ident("rest"),
+								Body: &BlockStatement{Statements: []Statement{
+									&ExpressionStatement{Expression: &InfixExpression{Left: ident("a"), Operator: "+", Right: integer(2)}},
+								}},
+							},
+							{
+								Pattern: &WildcardPattern{},
+								Body: &BlockStatement{Statements: []Statement{
+									&ExpressionStatement{Expression: &MemberExpression{Left: ident("p"), Member: ident("x")}},
+								}},
+							},
+						},
+					}},
+				}},
+			},
+		},
+	}}
+
+	var names []string
+	var integers []int64
+	nils := 0
+	Inspect(program, func(node Node) bool {
+		switch node := node.(type) {
+		case nil:
+			nils++
+		case *Identifier:
+			names = append(names, node.Value)
+		case *IntegerLiteral:
+			integers = append(integers, node.Value)
+		}
+		return true
+	})
+
+	expectedNames := []string{"f", "a", "rest", "a", "rest", "a", "p", "x"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("identifiers wrong. want=%v, got=%v", expectedNames, names)
+	}
+	if !reflect.DeepEqual(integers, []int64{1, 2}) {
+		t.Errorf("integers wrong. want=%v, got=%v", []int64{1, 2}, integers)
+	}
+
+	visited := 0
+	Inspect(program, func(node Node) bool {
+		if node != nil {
+			visited++
+		}
+		return true
+	})
+	if nils != visited {
+		t.Errorf("every visited node should be followed by f(nil). visited=%d, nils=%d", visited, nils)
+	}
+
+	var skipped []string
+	Inspect(program, func(node Node) bool {
+		if ident, ok := node.(*Identifier); ok {
+			skipped = append(skipped, ident.Value)
+		}
+		_, isFunction := node.(*FunctionLiteral)
+		return !isFunction
+	})
+	if !reflect.DeepEqual(skipped, []string{"f"}) {
+		t.Errorf("returning false should skip children. got=%v", skipped)
+	}
+}
+
+func TestApply(t *testing.T) {
+	integer := func(v int64) *IntegerLiteral {
+		return &IntegerLiteral{Token: token.Token{Type: token.INT, Literal: strconv.FormatInt(v, 10)}, Value: v}
+	}
+
+	// (1 + (2 * 3))
+	newTree := func() Node {
+		return &InfixExpression{
+			Left:     integer(1),
+			Operator: "+",
+			Right: &InfixExpression{
+				Left:     integer(2),
+				Operator: "*",
+				Right:    integer(3),
+			},
+		}
+	}
+	double := func(c *Cursor) bool {
+		if literal, ok := c.Node().(*IntegerLiteral); ok {
+			c.Replace(integer(literal.Value * 2))
+		}
+		return true
+	}
+
+	tests := []struct {
+		name      string
+		pre, post ApplyFunc
+		expected  string
+	}{
+		{"pre", double, nil, "(2 + (4 * 6))"},
+		{"post", nil, double, "(2 + (4 * 6))"},
+		{
+			"pre skips children",
+			func(c *Cursor) bool {
+				infix, ok := c.Node().(*InfixExpression)
+				return !ok || infix.Operator != "*"
+			},
+			double,
+			"(2 + (2 * 3))",
+		},
+		{
+			"post aborts",
+			nil,
+			func(c *Cursor) bool {
+				double(c)
+				_, ok := c.Node().(*IntegerLiteral)
+				return !ok || c.Node().(*IntegerLiteral).Value != 4
+			},
+			"(2 + (4 * 3))",
+		},
+		{
+			"replace root",
+			nil,
+			func(c *Cursor) bool {
+				if c.Parent() == nil {
+					c.Replace(&Identifier{Value: "root"})
+				}
+				return true
+			},
+			"root",
+		},
+	}
+
+	for _, tt := range tests {
+		result := Apply(newTree(), tt.pre, tt.post)
+		if result.String() != tt.expected {
+			t.Errorf("%s: expected=%q, got=%q", tt.name, tt.expected, result.String())
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("replacing an expression with a statement should panic")
+		}
+	}()
+	Apply(newTree(), func(c *Cursor) bool {
+		if _, ok := c.Node().(*IntegerLiteral); ok {
+			c.Replace(&ReturnStatement{})
+		}
+		return true
+	}, nil)
+}
+
+func TestCopy(t *testing.T) {
+	ident := func(name string) *Identifier {
+		return &Identifier{Token: token.Token{Type: token.IDENT, Literal: name}, Value: name}
+	}
+	original := &Program{Statements: []Statement{
+		&ExpressionStatement{Expression: &CallExpression{
+			Token:     token.Token{Type: token.LPAREN, Literal: "("},
+			Function:  ident("f"),
+			Arguments: []Expression{ident("a"), &HashLiteral{Pairs: map[Expression]Expression{ident("k"): ident("v")}}},
+		}},
+	}}
+	want := original.String()
+
+	copied := Copy(original)
+	Modify(copied, func(node Node) Node {
+		if _, ok := node.(*Identifier); ok {
+			return ident("x")
+		}
+		return node
+	})
+
+	if original.String() != want {
+		t.Errorf("original changed by modifying the copy. got=%q, want=%q", original.String(), want)
+	}
+	if got := copied.String(); got != "x(x, {x:x})" {
+		t.Errorf("copy not modified. got=%q", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 type BlockStatement struct {
 	Token      token.Token
 	Statements []Statement
+	Rbrace     token.Position // zero for bodies written without braces
 }
 
 func (bs *BlockStatement) statementNode() {}
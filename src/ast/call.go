@@ -11,6 +11,10 @@ type CallExpression struct {
 	Token     token.Token // The '(' token
 	Function  Expression  // Identifier or FunctionLiteral
 	Arguments []Expression
+	Rparen    token.Position // zero for calls not parsed from source
+	// OptionalChain is set when the call continues an optional chain such
+	// as a?.f(), which makes it null, without calling anything, if a is.
+	OptionalChain bool
 }
 
 func (ce *CallExpression) expressionNode() {}
@@ -0,0 +1,35 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// ConditionalExpression is the ternary form cond ? a : b.
+type ConditionalExpression struct {
+	Token       token.Token // The '?' token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (ce *ConditionalExpression) expressionNode() {}
+
+func (ce *ConditionalExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+
+func (ce *ConditionalExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ce.Condition.String())
+	out.WriteString(" ? ")
+	out.WriteString(ce.Consequence.String())
+	out.WriteString(" : ")
+	out.WriteString(ce.Alternative.String())
+	out.WriteString(")")
+
+	return out.String()
+}
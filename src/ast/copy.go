@@ -0,0 +1,169 @@
+package ast
+
+import "fmt"
+
+// Copy returns a deep copy of the tree rooted at node, so that the copy can
+// be modified in place without changing node. Tokens are copied by value.
+func Copy(node Node) Node {
+	return Apply(node, func(c *Cursor) bool {
+		c.Replace(shallowCopy(c.Node()))
+		return true
+	}, nil)
+}
+
+// shallowCopy copies node itself together with the slices, maps and
+// helper structs that hold its children, which Apply then replaces with
+// copies of their own.
+func shallowCopy(node Node) Node {
+	switch n := node.(type) {
+
+	// Statements
+	case *Program:
+		c := *n
+		c.Statements = append([]Statement(nil), n.Statements...)
+		return &c
+	case *ExpressionStatement:
+		c := *n
+		return &c
+	case *BlockStatement:
+		c := *n
+		c.Statements = append([]Statement(nil), n.Statements...)
+		return &c
+	case *ReturnStatement:
+		c := *n
+		return &c
+	case *LetStatement:
+		c := *n
+		return &c
+	case *ThrowStatement:
+		c := *n
+		return &c
+	case *StructStatement:
+		c := *n
+		c.Fields = append([]*Identifier(nil), n.Fields...)
+		return &c
+	case *ImplStatement:
+		c := *n
+		c.Methods = make([]*StructMethod, len(n.Methods))
+		for i, m := range n.Methods {
+			method := *m
+			c.Methods[i] = &method
+		}
+		return &c
+	case *ImportStatement:
+		c := *n
+		return &c
+	case *ExportStatement:
+		c := *n
+		return &c
+
+	// Literals
+	case *Identifier:
+		c := *n
+		return &c
+	case *IntegerLiteral:
+		c := *n
+		return &c
+	case *StringLiteral:
+		c := *n
+		return &c
+	case *Boolean:
+		c := *n
+		return &c
+	case *ArrayLiteral:
+		c := *n
+		c.Elements = append([]Expression(nil), n.Elements...)
+		return &c
+	case *HashLiteral:
+		c := *n
+		c.Pairs = make(map[Expression]Expression, len(n.Pairs))
+		for key, value := range n.Pairs {
+			c.Pairs[key] = value
+		}
+		return &c
+	case *FunctionLiteral:
+		c := *n
+		c.Parameters = append([]*Identifier(nil), n.Parameters...)
+		if n.Patterns != nil {
+			c.Patterns = append([]Pattern(nil), n.Patterns...)
+		}
+		return &c
+	case *MacroLiteral:
+		c := *n
+		c.Parameters = append([]*Identifier(nil), n.Parameters...)
+		return &c
+
+	// Expressions
+	case *PrefixExpression:
+		c := *n
+		return &c
+	case *InfixExpression:
+		c := *n
+		return &c
+	case *IfExpression:
+		c := *n
+		return &c
+	case *ConditionalExpression:
+		c := *n
+		return &c
+	case *CallExpression:
+		c := *n
+		c.Arguments = append([]Expression(nil), n.Arguments...)
+		return &c
+	case *IndexExpression:
+		c := *n
+		return &c
+	case *OptionalIndexExpression:
+		c := *n
+		return &c
+	case *MemberExpression:
+		c := *n
+		return &c
+	case *SliceExpression:
+		c := *n
+		return &c
+	case *RangeExpression:
+		c := *n
+		return &c
+	case *YieldExpression:
+		c := *n
+		return &c
+	case *TryExpression:
+		c := *n
+		return &c
+	case *MatchExpression:
+		c := *n
+		c.Arms = make([]*MatchArm, len(n.Arms))
+		for i, a := range n.Arms {
+			arm := *a
+			c.Arms[i] = &arm
+		}
+		return &c
+
+	// Patterns
+	case *WildcardPattern:
+		c := *n
+		return &c
+	case *BindingPattern:
+		c := *n
+		return &c
+	case *LiteralPattern:
+		c := *n
+		return &c
+	case *TypePattern:
+		c := *n
+		return &c
+	case *ArrayPattern:
+		c := *n
+		c.Elements = append([]Pattern(nil), n.Elements...)
+		return &c
+	case *HashPattern:
+		c := *n
+		c.Keys = append([]Expression(nil), n.Keys...)
+		c.Values = append([]Pattern(nil), n.Values...)
+		return &c
+
+	default:
+		panic(fmt.Sprintf("ast: unexpected node type %T", n))
+	}
+}
@@ -10,7 +10,13 @@ import (
 type FunctionLiteral struct {
 	Token      token.Token // The 'fn' token
 	Parameters []*Identifier
-	Body       *BlockStatement
+	// Patterns holds the destructuring pattern of each parameter written as
+	// [a, b] or {name}, and nil for plain identifiers. It is nil when no
+	// parameter is destructured.
+	Patterns    []Pattern
+	Body        *BlockStatement
+	IsGenerator bool // declared with fn*
+	IsArrow     bool // written as x => ... or (x, y) => ...
 }
 
 func (fl *FunctionLiteral) expressionNode() {}
@@ -28,6 +34,9 @@ func (fl *FunctionLiteral) String() string {
 	}
 
 	out.WriteString(fl.TokenLiteral())
+	if fl.IsGenerator {
+		out.WriteString("*")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(")")
@@ -2,14 +2,16 @@ package ast
 
 import (
 	"bytes"
+	"sort"
 	"strings"
 
 	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
 )
 
 type HashLiteral struct {
-	Token token.Token // the '{' token
-	Pairs map[Expression]Expression
+	Token  token.Token // the '{' token
+	Pairs  map[Expression]Expression
+	Rbrace token.Position // zero for hashes not parsed from source
 }
 
 func (hl *HashLiteral) expressionNode() {}
@@ -22,8 +24,8 @@ func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, key := range hl.Keys() {
+		pairs = append(pairs, key.String()+":"+hl.Pairs[key].String())
 	}
 
 	out.WriteString("{")
@@ -32,3 +34,22 @@ func (hl *HashLiteral) String() string {
 
 	return out.String()
 }
+
+// Keys returns the keys of hl in source order. Keys without a position,
+// e.g. ones built by macros, come first, ordered by their String().
+func (hl *HashLiteral) Keys() []Expression {
+	keys := make([]Expression, 0, len(hl.Pairs))
+	for key := range hl.Pairs {
+		keys = append(keys, key)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		pi, pj := Pos(keys[i]), Pos(keys[j])
+		if pi != pj {
+			return before(pi, pj)
+		}
+		return keys[i].String() < keys[j].String()
+	})
+
+	return keys
+}
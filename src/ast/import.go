@@ -0,0 +1,49 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// ImportStatement implements ast.Statement interface.
+type ImportStatement struct {
+	Token token.Token // The 'import' token
+	Path  *StringLiteral
+	Alias *Identifier
+}
+
+func (is *ImportStatement) statementNode() {}
+
+func (is *ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+func (is *ImportStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(is.TokenLiteral() + " ")
+	out.WriteString(`"` + is.Path.Value + `"`)
+	out.WriteString(" as ")
+	out.WriteString(is.Alias.String())
+	out.WriteString(";")
+
+	return out.String()
+}
+
+// ExportStatement marks the bindings of a top-level let or struct
+// declaration as visible to modules importing this file.
+type ExportStatement struct {
+	Token     token.Token // The 'export' token
+	Statement Statement
+}
+
+func (es *ExportStatement) statementNode() {}
+
+func (es *ExportStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+
+func (es *ExportStatement) String() string {
+	return es.TokenLiteral() + " " + es.Statement.String()
+}
@@ -10,6 +10,9 @@ type IndexExpression struct {
 	Token token.Token // The [ token
 	Left  Expression
 	Index Expression
+	// OptionalChain is set when the expression continues an optional
+	// chain such as a?.b[0], which makes it null if a is.
+	OptionalChain bool
 }
 
 func (ie *IndexExpression) expressionNode() {}
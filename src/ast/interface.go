@@ -14,3 +14,8 @@ type Expression interface {
 	Node
 	expressionNode()
 }
+
+type Pattern interface {
+	Node
+	patternNode()
+}
@@ -7,10 +7,13 @@ import (
 )
 
 // LetStatement implements ast.Statement interface.
+// A destructuring let such as let [a, b] = xs; leaves Name nil and
+// sets Pattern instead.
 type LetStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token   token.Token
+	Name    *Identifier
+	Pattern Pattern
+	Value   Expression
 }
 
 func (ls *LetStatement) statementNode() {}
@@ -23,7 +26,11 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
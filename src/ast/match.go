@@ -0,0 +1,59 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// MatchExpression implements ast.Expression interface.
+type MatchExpression struct {
+	Token token.Token // The 'match' token
+	Value Expression
+	Arms  []*MatchArm
+}
+
+// MatchArm is a single `pattern if guard => body` clause of a match.
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression // nil when the arm has no guard
+	Body    *BlockStatement
+}
+
+func (me *MatchExpression) expressionNode() {}
+
+func (me *MatchExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match (")
+	out.WriteString(me.Value.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+
+	return out.String()
+}
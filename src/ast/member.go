@@ -0,0 +1,35 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// MemberExpression is a field or method access such as p.x.
+type MemberExpression struct {
+	Token  token.Token // The '.' token
+	Left   Expression
+	Member *Identifier
+	// OptionalChain is set when the expression continues an optional
+	// chain such as a?.b.c, which makes it null if a is.
+	OptionalChain bool
+}
+
+func (me *MemberExpression) expressionNode() {}
+
+func (me *MemberExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+func (me *MemberExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(me.Left.String())
+	out.WriteString(".")
+	out.WriteString(me.Member.String())
+	out.WriteString(")")
+
+	return out.String()
+}
@@ -0,0 +1,13 @@
+package ast
+
+type ModifierFunc func(Node) Node
+
+// Modify walks the tree rooted at node depth-first, replacing every node
+// with the result of calling modifier on it after its children have been
+// modified.
+func Modify(node Node, modifier ModifierFunc) Node {
+	return Apply(node, nil, func(c *Cursor) bool {
+		c.Replace(modifier(c.Node()))
+		return true
+	})
+}
@@ -0,0 +1,41 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// OptionalIndexExpression is a?["k"] or a?.k. It evaluates to null instead
+// of indexing when Left is null, and so do the index, member, call and
+// slice expressions after it in the same chain. Otherwise a?.k reads the
+// member k like a.k does, and a?["k"] indexes like a["k"].
+type OptionalIndexExpression struct {
+	Token token.Token // The '?[' or '?.' token
+	Left  Expression
+	Index Expression
+}
+
+func (oe *OptionalIndexExpression) expressionNode() {}
+
+func (oe *OptionalIndexExpression) TokenLiteral() string {
+	return oe.Token.Literal
+}
+
+func (oe *OptionalIndexExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(oe.Left.String())
+	if oe.Token.Type == token.OPTIONAL_DOT {
+		out.WriteString("?.")
+		out.WriteString(oe.Index.String())
+	} else {
+		out.WriteString("?[")
+		out.WriteString(oe.Index.String())
+		out.WriteString("]")
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
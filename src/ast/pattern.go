@@ -0,0 +1,133 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// WildcardPattern matches any value without binding it.
+type WildcardPattern struct {
+	Token token.Token // The '_' token
+}
+
+func (wp *WildcardPattern) patternNode() {}
+
+func (wp *WildcardPattern) TokenLiteral() string {
+	return wp.Token.Literal
+}
+
+func (wp *WildcardPattern) String() string {
+	return "_"
+}
+
+// BindingPattern matches any value and binds it to Name.
+type BindingPattern struct {
+	Token token.Token
+	Name  *Identifier
+}
+
+func (bp *BindingPattern) patternNode() {}
+
+func (bp *BindingPattern) TokenLiteral() string {
+	return bp.Token.Literal
+}
+
+func (bp *BindingPattern) String() string {
+	return bp.Name.String()
+}
+
+// LiteralPattern matches values equal to an integer, string or boolean literal.
+type LiteralPattern struct {
+	Token token.Token
+	Value Expression
+}
+
+func (lp *LiteralPattern) patternNode() {}
+
+func (lp *LiteralPattern) TokenLiteral() string {
+	return lp.Token.Literal
+}
+
+func (lp *LiteralPattern) String() string {
+	return lp.Value.String()
+}
+
+// TypePattern matches values of the object type TypeName, e.g. n: INTEGER.
+// Name is nil when the value is not bound, as in _: STRING.
+type TypePattern struct {
+	Token    token.Token
+	Name     *Identifier
+	TypeName string
+}
+
+func (tp *TypePattern) patternNode() {}
+
+func (tp *TypePattern) TokenLiteral() string {
+	return tp.Token.Literal
+}
+
+func (tp *TypePattern) String() string {
+	if tp.Name == nil {
+		return "_: " + tp.TypeName
+	}
+	return tp.Name.String() + ": " + tp.TypeName
+}
+
+// ArrayPattern matches arrays element by element. Without Rest the array
+// must have exactly as many elements; with Rest the remaining elements are
+// bound to it as a new array.
+type ArrayPattern struct {
+	Token    token.Token // The '[' token
+	Elements []Pattern
+	Rest     *Identifier
+}
+
+func (ap *ArrayPattern) patternNode() {}
+
+func (ap *ArrayPattern) TokenLiteral() string {
+	return ap.Token.Literal
+}
+
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// HashPattern matches hashes that contain every key in Keys, matching each
+// value against the pattern at the same position in Values. Keys not listed
+// in the pattern are ignored.
+type HashPattern struct {
+	Token  token.Token // The '{' token
+	Keys   []Expression
+	Values []Pattern
+}
+
+func (hp *HashPattern) patternNode() {}
+
+func (hp *HashPattern) TokenLiteral() string {
+	return hp.Token.Literal
+}
+
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for i, key := range hp.Keys {
+		pairs = append(pairs, key.String()+": "+hp.Values[i].String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
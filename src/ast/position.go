@@ -0,0 +1,102 @@
+package ast
+
+import "github.com/g-hyoga/writing-interpreter-in-go/src/token"
+
+// Pos returns the position of the first token of node. Nodes that were
+// not parsed from source, e.g. ones built by macros, have the zero
+// Position.
+func Pos(node Node) token.Position {
+	switch n := node.(type) {
+
+	// Nodes starting with a child
+	case *Program:
+		if len(n.Statements) > 0 {
+			return Pos(n.Statements[0])
+		}
+		return token.Position{}
+	case *InfixExpression:
+		return Pos(n.Left)
+	case *ConditionalExpression:
+		return Pos(n.Condition)
+	case *CallExpression:
+		return Pos(n.Function)
+	case *IndexExpression:
+		return Pos(n.Left)
+	case *OptionalIndexExpression:
+		return Pos(n.Left)
+	case *MemberExpression:
+		return Pos(n.Left)
+	case *SliceExpression:
+		return Pos(n.Left)
+	case *RangeExpression:
+		return Pos(n.Start)
+
+	// Nodes starting with their own token
+	case *ExpressionStatement:
+		return n.Token.Pos
+	case *BlockStatement:
+		return n.Token.Pos
+	case *ReturnStatement:
+		return n.Token.Pos
+	case *LetStatement:
+		return n.Token.Pos
+	case *ThrowStatement:
+		return n.Token.Pos
+	case *StructStatement:
+		return n.Token.Pos
+	case *ImplStatement:
+		return n.Token.Pos
+	case *ImportStatement:
+		return n.Token.Pos
+	case *ExportStatement:
+		return n.Token.Pos
+	case *Identifier:
+		return n.Token.Pos
+	case *IntegerLiteral:
+		return n.Token.Pos
+	case *StringLiteral:
+		return n.Token.Pos
+	case *Boolean:
+		return n.Token.Pos
+	case *ArrayLiteral:
+		return n.Token.Pos
+	case *HashLiteral:
+		return n.Token.Pos
+	case *FunctionLiteral:
+		return n.Token.Pos
+	case *MacroLiteral:
+		return n.Token.Pos
+	case *PrefixExpression:
+		return n.Token.Pos
+	case *IfExpression:
+		return n.Token.Pos
+	case *YieldExpression:
+		return n.Token.Pos
+	case *TryExpression:
+		return n.Token.Pos
+	case *MatchExpression:
+		return n.Token.Pos
+	case *WildcardPattern:
+		return n.Token.Pos
+	case *BindingPattern:
+		return n.Token.Pos
+	case *LiteralPattern:
+		return n.Token.Pos
+	case *TypePattern:
+		return n.Token.Pos
+	case *ArrayPattern:
+		return n.Token.Pos
+	case *HashPattern:
+		return n.Token.Pos
+	}
+
+	return token.Position{}
+}
+
+// before reports whether a comes before b in the source.
+func before(a, b token.Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
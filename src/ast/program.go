@@ -1,9 +1,14 @@
 package ast
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
 
 type Program struct {
 	Statements []Statement
+	Comments   []token.Token // every // comment in the source, in order
 }
 
 func (p *Program) TokenLiteral() string {
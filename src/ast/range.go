@@ -0,0 +1,38 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// RangeExpression implements ast.Expression interface.
+type RangeExpression struct {
+	Token     token.Token // The '..' or '..<' token
+	Start     Expression
+	End       Expression
+	Step      Expression // nil when no step is given
+	Inclusive bool
+}
+
+func (re *RangeExpression) expressionNode() {}
+
+func (re *RangeExpression) TokenLiteral() string {
+	return re.Token.Literal
+}
+
+func (re *RangeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(re.Start.String())
+	out.WriteString(re.Token.Literal)
+	out.WriteString(re.End.String())
+	if re.Step != nil {
+		out.WriteString(" step ")
+		out.WriteString(re.Step.String())
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
@@ -0,0 +1,43 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// SliceExpression implements ast.Expression interface.
+// Start and End are nil when omitted, e.g. arr[:2] or arr[1:].
+type SliceExpression struct {
+	Token token.Token // The [ token
+	Left  Expression
+	Start Expression
+	End   Expression
+	// OptionalChain is set when the expression continues an optional
+	// chain such as a?.b[1:], which makes it null if a is.
+	OptionalChain bool
+}
+
+func (se *SliceExpression) expressionNode() {}
+
+func (se *SliceExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
@@ -0,0 +1,96 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// StructStatement declares a struct type and binds its constructor, e.g.
+// struct Point { x, y }.
+type StructStatement struct {
+	Token  token.Token // The 'struct' token
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (ss *StructStatement) statementNode() {}
+
+func (ss *StructStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+
+func (ss *StructStatement) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, f := range ss.Fields {
+		fields = append(fields, f.String())
+	}
+
+	out.WriteString(ss.TokenLiteral() + " ")
+	out.WriteString(ss.Name.String())
+	if len(fields) == 0 {
+		out.WriteString(" {}")
+	} else {
+		out.WriteString(" { " + strings.Join(fields, ", ") + " }")
+	}
+
+	return out.String()
+}
+
+// ImplStatement attaches methods to a struct declared earlier. Inside a
+// method body the instance it was called on is bound to self.
+type ImplStatement struct {
+	Token   token.Token // The 'impl' token
+	Name    *Identifier
+	Methods []*StructMethod
+}
+
+// StructMethod is a single name(params) { body } entry of an impl block.
+type StructMethod struct {
+	Name     *Identifier
+	Function *FunctionLiteral
+}
+
+func (is *ImplStatement) statementNode() {}
+
+func (is *ImplStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+func (is *ImplStatement) String() string {
+	var out bytes.Buffer
+
+	methods := []string{}
+	for _, m := range is.Methods {
+		methods = append(methods, m.String())
+	}
+
+	out.WriteString(is.TokenLiteral() + " ")
+	out.WriteString(is.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(methods, " "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+func (sm *StructMethod) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range sm.Function.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString(sm.Name.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") { ")
+	out.WriteString(sm.Function.Body.String())
+	out.WriteString(" }")
+
+	return out.String()
+}
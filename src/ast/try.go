@@ -0,0 +1,46 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// TryExpression implements ast.Expression interface.
+// Either Catch or Finally may be nil, but not both.
+type TryExpression struct {
+	Token      token.Token // The 'try' token
+	Block      *BlockStatement
+	CatchParam *Identifier // nil for a catch block without a binding
+	Catch      *BlockStatement
+	Finally    *BlockStatement
+}
+
+func (te *TryExpression) expressionNode() {}
+
+func (te *TryExpression) TokenLiteral() string {
+	return te.Token.Literal
+}
+
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(te.Block.String())
+
+	if te.Catch != nil {
+		out.WriteString(" catch")
+		if te.CatchParam != nil {
+			out.WriteString("(" + te.CatchParam.String() + ")")
+		}
+		out.WriteString(" ")
+		out.WriteString(te.Catch.String())
+	}
+
+	if te.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(te.Finally.String())
+	}
+
+	return out.String()
+}
@@ -0,0 +1,294 @@
+package ast
+
+import "fmt"
+
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order: It starts by calling
+// v.Visit(node); node must not be nil. If the visitor w returned by
+// v.Visit(node) is not nil, Walk is invoked recursively with visitor
+// w for each of the non-nil children of node, followed by a call of
+// w.Visit(nil).
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	eachChild(node, func(child Node, _ func(Node)) {
+		Walk(v, child)
+	})
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order: It starts by calling
+// f(node); node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the non-nil children of node, followed by a
+// call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// eachChild calls fn for every non-nil child of node in source order,
+// together with a function that replaces that child in node. This is the
+// one place that knows the shape of every node type; Walk, Inspect and
+// Apply are all built on it.
+func eachChild(node Node, fn func(child Node, replace func(Node))) {
+	switch n := node.(type) {
+
+	// Statements
+	case *Program:
+		for i := range n.Statements {
+			i := i
+			fn(n.Statements[i], func(r Node) { n.Statements[i] = asStatement(r) })
+		}
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			fn(n.Expression, func(r Node) { n.Expression = asExpression(r) })
+		}
+	case *BlockStatement:
+		for i := range n.Statements {
+			i := i
+			fn(n.Statements[i], func(r Node) { n.Statements[i] = asStatement(r) })
+		}
+	case *ReturnStatement:
+		if n.ReturnValue != nil {
+			fn(n.ReturnValue, func(r Node) { n.ReturnValue = asExpression(r) })
+		}
+	case *LetStatement:
+		if n.Name != nil {
+			fn(n.Name, func(r Node) { n.Name = asIdentifier(r) })
+		}
+		if n.Pattern != nil {
+			fn(n.Pattern, func(r Node) { n.Pattern = asPattern(r) })
+		}
+		if n.Value != nil {
+			fn(n.Value, func(r Node) { n.Value = asExpression(r) })
+		}
+	case *ThrowStatement:
+		if n.Value != nil {
+			fn(n.Value, func(r Node) { n.Value = asExpression(r) })
+		}
+	case *StructStatement:
+		fn(n.Name, func(r Node) { n.Name = asIdentifier(r) })
+		for i := range n.Fields {
+			i := i
+			fn(n.Fields[i], func(r Node) { n.Fields[i] = asIdentifier(r) })
+		}
+	case *ImplStatement:
+		fn(n.Name, func(r Node) { n.Name = asIdentifier(r) })
+		for _, m := range n.Methods {
+			m := m
+			fn(m.Name, func(r Node) { m.Name = asIdentifier(r) })
+			fn(m.Function, func(r Node) { m.Function = asFunctionLiteral(r) })
+		}
+	case *ImportStatement:
+		fn(n.Path, func(r Node) { n.Path = asStringLiteral(r) })
+		fn(n.Alias, func(r Node) { n.Alias = asIdentifier(r) })
+	case *ExportStatement:
+		fn(n.Statement, func(r Node) { n.Statement = asStatement(r) })
+
+	// Literals
+	case *Identifier, *IntegerLiteral, *StringLiteral, *Boolean:
+		// leaves
+	case *ArrayLiteral:
+		for i := range n.Elements {
+			i := i
+			fn(n.Elements[i], func(r Node) { n.Elements[i] = asExpression(r) })
+		}
+	case *HashLiteral:
+		for _, key := range n.Keys() {
+			key := key
+			fn(key, func(r Node) {
+				value := n.Pairs[key]
+				delete(n.Pairs, key)
+				key = asExpression(r)
+				n.Pairs[key] = value
+			})
+			fn(n.Pairs[key], func(r Node) { n.Pairs[key] = asExpression(r) })
+		}
+	case *FunctionLiteral:
+		for i := range n.Parameters {
+			i := i
+			if i < len(n.Patterns) && n.Patterns[i] != nil {
+				fn(n.Patterns[i], func(r Node) { n.Patterns[i] = asPattern(r) })
+				continue
+			}
+			fn(n.Parameters[i], func(r Node) { n.Parameters[i] = asIdentifier(r) })
+		}
+		if n.Body != nil {
+			fn(n.Body, func(r Node) { n.Body = asBlockStatement(r) })
+		}
+	case *MacroLiteral:
+		for i := range n.Parameters {
+			i := i
+			fn(n.Parameters[i], func(r Node) { n.Parameters[i] = asIdentifier(r) })
+		}
+		if n.Body != nil {
+			fn(n.Body, func(r Node) { n.Body = asBlockStatement(r) })
+		}
+
+	// Expressions
+	case *PrefixExpression:
+		fn(n.Right, func(r Node) { n.Right = asExpression(r) })
+	case *InfixExpression:
+		fn(n.Left, func(r Node) { n.Left = asExpression(r) })
+		fn(n.Right, func(r Node) { n.Right = asExpression(r) })
+	case *IfExpression:
+		fn(n.Condition, func(r Node) { n.Condition = asExpression(r) })
+		fn(n.Consequence, func(r Node) { n.Consequence = asBlockStatement(r) })
+		if n.Alternative != nil {
+			fn(n.Alternative, func(r Node) { n.Alternative = asBlockStatement(r) })
+		}
+	case *ConditionalExpression:
+		fn(n.Condition, func(r Node) { n.Condition = asExpression(r) })
+		fn(n.Consequence, func(r Node) { n.Consequence = asExpression(r) })
+		fn(n.Alternative, func(r Node) { n.Alternative = asExpression(r) })
+	case *CallExpression:
+		fn(n.Function, func(r Node) { n.Function = asExpression(r) })
+		for i := range n.Arguments {
+			i := i
+			fn(n.Arguments[i], func(r Node) { n.Arguments[i] = asExpression(r) })
+		}
+	case *IndexExpression:
+		fn(n.Left, func(r Node) { n.Left = asExpression(r) })
+		fn(n.Index, func(r Node) { n.Index = asExpression(r) })
+	case *OptionalIndexExpression:
+		fn(n.Left, func(r Node) { n.Left = asExpression(r) })
+		fn(n.Index, func(r Node) { n.Index = asExpression(r) })
+	case *MemberExpression:
+		fn(n.Left, func(r Node) { n.Left = asExpression(r) })
+		fn(n.Member, func(r Node) { n.Member = asIdentifier(r) })
+	case *SliceExpression:
+		fn(n.Left, func(r Node) { n.Left = asExpression(r) })
+		if n.Start != nil {
+			fn(n.Start, func(r Node) { n.Start = asExpression(r) })
+		}
+		if n.End != nil {
+			fn(n.End, func(r Node) { n.End = asExpression(r) })
+		}
+	case *RangeExpression:
+		fn(n.Start, func(r Node) { n.Start = asExpression(r) })
+		fn(n.End, func(r Node) { n.End = asExpression(r) })
+		if n.Step != nil {
+			fn(n.Step, func(r Node) { n.Step = asExpression(r) })
+		}
+	case *YieldExpression:
+		if n.Value != nil {
+			fn(n.Value, func(r Node) { n.Value = asExpression(r) })
+		}
+	case *TryExpression:
+		fn(n.Block, func(r Node) { n.Block = asBlockStatement(r) })
+		if n.CatchParam != nil {
+			fn(n.CatchParam, func(r Node) { n.CatchParam = asIdentifier(r) })
+		}
+		if n.Catch != nil {
+			fn(n.Catch, func(r Node) { n.Catch = asBlockStatement(r) })
+		}
+		if n.Finally != nil {
+			fn(n.Finally, func(r Node) { n.Finally = asBlockStatement(r) })
+		}
+	case *MatchExpression:
+		fn(n.Value, func(r Node) { n.Value = asExpression(r) })
+		for _, arm := range n.Arms {
+			arm := arm
+			fn(arm.Pattern, func(r Node) { arm.Pattern = asPattern(r) })
+			if arm.Guard != nil {
+				fn(arm.Guard, func(r Node) { arm.Guard = asExpression(r) })
+			}
+			fn(arm.Body, func(r Node) { arm.Body = asBlockStatement(r) })
+		}
+
+	// Patterns
+	case *WildcardPattern:
+		// leaf
+	case *BindingPattern:
+		fn(n.Name, func(r Node) { n.Name = asIdentifier(r) })
+	case *LiteralPattern:
+		fn(n.Value, func(r Node) { n.Value = asExpression(r) })
+	case *TypePattern:
+		if n.Name != nil {
+			fn(n.Name, func(r Node) { n.Name = asIdentifier(r) })
+		}
+	case *ArrayPattern:
+		for i := range n.Elements {
+			i := i
+			fn(n.Elements[i], func(r Node) { n.Elements[i] = asPattern(r) })
+		}
+		if n.Rest != nil {
+			fn(n.Rest, func(r Node) { n.Rest = asIdentifier(r) })
+		}
+	case *HashPattern:
+		for i := range n.Keys {
+			i := i
+			fn(n.Keys[i], func(r Node) { n.Keys[i] = asExpression(r) })
+			fn(n.Values[i], func(r Node) { n.Values[i] = asPattern(r) })
+		}
+
+	default:
+		panic(fmt.Sprintf("ast: unexpected node type %T", n))
+	}
+}
+
+func asStatement(n Node) Statement {
+	if s, ok := n.(Statement); ok {
+		return s
+	}
+	panic(fmt.Sprintf("ast: cannot replace a statement with %T", n))
+}
+
+func asExpression(n Node) Expression {
+	if e, ok := n.(Expression); ok {
+		return e
+	}
+	panic(fmt.Sprintf("ast: cannot replace an expression with %T", n))
+}
+
+func asPattern(n Node) Pattern {
+	if p, ok := n.(Pattern); ok {
+		return p
+	}
+	panic(fmt.Sprintf("ast: cannot replace a pattern with %T", n))
+}
+
+func asIdentifier(n Node) *Identifier {
+	if i, ok := n.(*Identifier); ok {
+		return i
+	}
+	panic(fmt.Sprintf("ast: cannot replace an identifier with %T", n))
+}
+
+func asBlockStatement(n Node) *BlockStatement {
+	if b, ok := n.(*BlockStatement); ok {
+		return b
+	}
+	panic(fmt.Sprintf("ast: cannot replace a block with %T", n))
+}
+
+func asFunctionLiteral(n Node) *FunctionLiteral {
+	if f, ok := n.(*FunctionLiteral); ok {
+		return f
+	}
+	panic(fmt.Sprintf("ast: cannot replace a function literal with %T", n))
+}
+
+func asStringLiteral(n Node) *StringLiteral {
+	if s, ok := n.(*StringLiteral); ok {
+		return s
+	}
+	panic(fmt.Sprintf("ast: cannot replace a string literal with %T", n))
+}
@@ -0,0 +1,31 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// YieldExpression implements ast.Expression interface.
+type YieldExpression struct {
+	Token token.Token // The 'yield' token
+	Value Expression  // nil for a bare yield
+}
+
+func (ye *YieldExpression) expressionNode() {}
+
+func (ye *YieldExpression) TokenLiteral() string {
+	return ye.Token.Literal
+}
+
+func (ye *YieldExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ye.TokenLiteral())
+	if ye.Value != nil {
+		out.WriteString(" ")
+		out.WriteString(ye.Value.String())
+	}
+
+	return out.String()
+}
@@ -0,0 +1,446 @@
+// Package astjson converts syntax trees to and from JSON for tools outside
+// the interpreter, such as editors and visualisers.
+//
+// Every node becomes an object holding its kind (the name of its Go type),
+// its token and the position of that token, and one member per field of
+// the node, named like the field with a lower-case first letter:
+//
+//	{
+//	  "kind": "InfixExpression",
+//	  "token": {"type": "+", "literal": "+"},
+//	  "pos": {"line": 1, "column": 3},
+//	  "left": {"kind": "Identifier", ...},
+//	  "operator": "+",
+//	  "right": {"kind": "IntegerLiteral", ...}
+//	}
+//
+// Hash literal pairs become a list of {"key": ..., "value": ...} objects in
+// source order. Missing children are null.
+package astjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// kinds maps the kind of every node, and of the clauses that nodes are made
+// of, to its type.
+var kinds = map[string]reflect.Type{}
+
+func init() {
+	for _, v := range []interface{}{
+		// Statements
+		&ast.Program{}, &ast.ExpressionStatement{}, &ast.BlockStatement{},
+		&ast.ReturnStatement{}, &ast.LetStatement{}, &ast.ThrowStatement{},
+		&ast.StructStatement{}, &ast.ImplStatement{}, &ast.StructMethod{},
+		&ast.ImportStatement{}, &ast.ExportStatement{},
+
+		// Literals
+		&ast.Identifier{}, &ast.IntegerLiteral{}, &ast.StringLiteral{},
+		&ast.Boolean{}, &ast.ArrayLiteral{}, &ast.HashLiteral{},
+		&ast.FunctionLiteral{}, &ast.MacroLiteral{},
+
+		// Expressions
+		&ast.PrefixExpression{}, &ast.InfixExpression{}, &ast.IfExpression{},
+		&ast.ConditionalExpression{}, &ast.CallExpression{},
+		&ast.IndexExpression{}, &ast.OptionalIndexExpression{},
+		&ast.MemberExpression{}, &ast.SliceExpression{}, &ast.RangeExpression{},
+		&ast.YieldExpression{}, &ast.TryExpression{}, &ast.MatchExpression{},
+		&ast.MatchArm{},
+
+		// Patterns
+		&ast.WildcardPattern{}, &ast.BindingPattern{}, &ast.LiteralPattern{},
+		&ast.TypePattern{}, &ast.ArrayPattern{}, &ast.HashPattern{},
+	} {
+		t := reflect.TypeOf(v).Elem()
+		kinds[t.Name()] = t
+	}
+}
+
+var (
+	tokenType    = reflect.TypeOf(token.Token{})
+	positionType = reflect.TypeOf(token.Position{})
+	pairsType    = reflect.TypeOf(map[ast.Expression]ast.Expression{})
+)
+
+type jsonToken struct {
+	Type    token.TokenType `json:"type"`
+	Literal string          `json:"literal"`
+}
+
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type jsonComment struct {
+	Text string        `json:"text"`
+	Pos  *jsonPosition `json:"pos,omitempty"`
+}
+
+type jsonPair struct {
+	Key   json.RawMessage `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// member is one name/value pair of an object; objects are written as
+// lists of members to keep "kind" first and fields in declaration order.
+type member struct {
+	name  string
+	value interface{}
+}
+
+type object []member
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var out bytes.Buffer
+
+	out.WriteString("{")
+	for i, m := range o {
+		if i > 0 {
+			out.WriteString(",")
+		}
+		name, _ := json.Marshal(m.name)
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(name)
+		out.WriteString(":")
+		out.Write(value)
+	}
+	out.WriteString("}")
+
+	return out.Bytes(), nil
+}
+
+// Marshal returns the JSON encoding of node.
+func Marshal(node ast.Node) ([]byte, error) {
+	v, err := encode(reflect.ValueOf(node))
+	if err != nil {
+		return nil, fmt.Errorf("astjson: %s", err)
+	}
+	return json.Marshal(v)
+}
+
+// MarshalIndent is like Marshal but indents the output.
+func MarshalIndent(node ast.Node, prefix, indent string) ([]byte, error) {
+	data, err := Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, prefix, indent); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// encode converts a pointer to a node or clause into an object.
+func encode(v reflect.Value) (interface{}, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.IsNil() {
+		return nil, nil
+	}
+
+	t := v.Elem().Type()
+	if kinds[t.Name()] != t {
+		return nil, fmt.Errorf("unsupported node type %s", v.Type())
+	}
+
+	obj := object{{"kind", t.Name()}}
+	for i := 0; i < t.NumField(); i++ {
+		field, fv := t.Field(i), v.Elem().Field(i)
+
+		if field.Type == tokenType {
+			tok := fv.Interface().(token.Token)
+			obj = append(obj, member{"token", jsonToken{tok.Type, tok.Literal}})
+			if pos := encodePosition(tok.Pos); pos != nil {
+				obj = append(obj, member{"pos", pos})
+			}
+			continue
+		}
+
+		value, err := encodeField(fv)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", t.Name(), field.Name, err)
+		}
+		obj = append(obj, member{fieldName(field.Name), value})
+	}
+
+	return obj, nil
+}
+
+func encodeField(v reflect.Value) (interface{}, error) {
+	switch {
+	case v.Type() == positionType:
+		return encodePosition(v.Interface().(token.Position)), nil
+
+	case v.Type() == pairsType:
+		if v.IsNil() {
+			return nil, nil
+		}
+		hl := &ast.HashLiteral{Pairs: v.Interface().(map[ast.Expression]ast.Expression)}
+		pairs := []object{}
+		for _, key := range hl.Keys() {
+			k, err := encode(reflect.ValueOf(key))
+			if err != nil {
+				return nil, err
+			}
+			value, err := encode(reflect.ValueOf(hl.Pairs[key]))
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, object{{"key", k}, {"value", value}})
+		}
+		return pairs, nil
+
+	case v.Type() == reflect.TypeOf([]token.Token{}):
+		if v.IsNil() {
+			return nil, nil
+		}
+		comments := []jsonComment{}
+		for _, c := range v.Interface().([]token.Token) {
+			comments = append(comments, jsonComment{c.Literal, encodePosition(c.Pos)})
+		}
+		return comments, nil
+
+	case v.Kind() == reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		list := []interface{}{}
+		for i := 0; i < v.Len(); i++ {
+			el, err := encodeField(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, el)
+		}
+		return list, nil
+
+	case v.Kind() == reflect.Ptr, v.Kind() == reflect.Interface:
+		return encode(v)
+
+	default:
+		return v.Interface(), nil
+	}
+}
+
+func encodePosition(pos token.Position) *jsonPosition {
+	if !pos.IsValid() {
+		return nil
+	}
+	return &jsonPosition{pos.Line, pos.Column}
+}
+
+// Unmarshal rebuilds the node encoded in data by Marshal.
+func Unmarshal(data []byte) (ast.Node, error) {
+	v, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("astjson: %s", err)
+	}
+	if !v.IsValid() {
+		return nil, fmt.Errorf("astjson: no node in input")
+	}
+
+	node, ok := v.Interface().(ast.Node)
+	if !ok {
+		return nil, fmt.Errorf("astjson: %s is not a node", v.Elem().Type().Name())
+	}
+	return node, nil
+}
+
+// UnmarshalProgram is like Unmarshal but requires data to hold a program.
+func UnmarshalProgram(data []byte) (*ast.Program, error) {
+	node, err := Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	program, ok := node.(*ast.Program)
+	if !ok {
+		return nil, fmt.Errorf("astjson: expected Program, got %T", node)
+	}
+	return program, nil
+}
+
+// decode returns a pointer to the node or clause encoded in data, or the
+// zero Value for null.
+func decode(data []byte) (reflect.Value, error) {
+	if isNull(data) {
+		return reflect.Value{}, nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return reflect.Value{}, err
+	}
+
+	var kind string
+	if err := json.Unmarshal(members["kind"], &kind); err != nil {
+		return reflect.Value{}, fmt.Errorf("missing kind")
+	}
+	t, ok := kinds[kind]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("unknown kind %q", kind)
+	}
+
+	v := reflect.New(t)
+	for i := 0; i < t.NumField(); i++ {
+		field, fv := t.Field(i), v.Elem().Field(i)
+
+		if field.Type == tokenType {
+			tok, err := decodeToken(members["token"], members["pos"])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%s.token: %s", kind, err)
+			}
+			fv.Set(reflect.ValueOf(tok))
+			continue
+		}
+
+		raw, ok := members[fieldName(field.Name)]
+		if !ok {
+			continue
+		}
+		if err := decodeField(raw, fv); err != nil {
+			return reflect.Value{}, fmt.Errorf("%s.%s: %s", kind, fieldName(field.Name), err)
+		}
+	}
+
+	return v, nil
+}
+
+func decodeField(data []byte, v reflect.Value) error {
+	if isNull(data) {
+		return nil
+	}
+
+	switch {
+	case v.Type() == positionType:
+		var pos jsonPosition
+		if err := json.Unmarshal(data, &pos); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(token.Position{Line: pos.Line, Column: pos.Column}))
+
+	case v.Type() == pairsType:
+		var pairs []jsonPair
+		if err := json.Unmarshal(data, &pairs); err != nil {
+			return err
+		}
+		m := make(map[ast.Expression]ast.Expression, len(pairs))
+		for _, pair := range pairs {
+			key, err := decodeExpression(pair.Key)
+			if err != nil {
+				return err
+			}
+			value, err := decodeExpression(pair.Value)
+			if err != nil {
+				return err
+			}
+			m[key] = value
+		}
+		v.Set(reflect.ValueOf(m))
+
+	case v.Type() == reflect.TypeOf([]token.Token{}):
+		var comments []jsonComment
+		if err := json.Unmarshal(data, &comments); err != nil {
+			return err
+		}
+		list := []token.Token{}
+		for _, c := range comments {
+			tok := token.Token{Type: token.COMMENT, Literal: c.Text}
+			if c.Pos != nil {
+				tok.Pos = token.Position{Line: c.Pos.Line, Column: c.Pos.Column}
+			}
+			list = append(list, tok)
+		}
+		v.Set(reflect.ValueOf(list))
+
+	case v.Kind() == reflect.Slice:
+		var elements []json.RawMessage
+		if err := json.Unmarshal(data, &elements); err != nil {
+			return err
+		}
+		list := reflect.MakeSlice(v.Type(), len(elements), len(elements))
+		for i, el := range elements {
+			if err := decodeField(el, list.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %s", i, err)
+			}
+		}
+		v.Set(list)
+
+	case v.Kind() == reflect.Ptr, v.Kind() == reflect.Interface:
+		child, err := decode(data)
+		if err != nil {
+			return err
+		}
+		if !child.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("cannot use %s as %s", child.Elem().Type().Name(), typeName(v.Type()))
+		}
+		v.Set(child)
+
+	default:
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+
+	return nil
+}
+
+func decodeExpression(data []byte) (ast.Expression, error) {
+	var exp ast.Expression
+	if err := decodeField(data, reflect.ValueOf(&exp).Elem()); err != nil {
+		return nil, err
+	}
+	return exp, nil
+}
+
+func decodeToken(tokenData, posData json.RawMessage) (token.Token, error) {
+	var tok jsonToken
+	if tokenData != nil {
+		if err := json.Unmarshal(tokenData, &tok); err != nil {
+			return token.Token{}, err
+		}
+	}
+
+	var pos jsonPosition
+	if posData != nil && !isNull(posData) {
+		if err := json.Unmarshal(posData, &pos); err != nil {
+			return token.Token{}, err
+		}
+	}
+
+	return token.Token{
+		Type:    tok.Type,
+		Literal: tok.Literal,
+		Pos:     token.Position{Line: pos.Line, Column: pos.Column},
+	}, nil
+}
+
+func isNull(data []byte) bool {
+	return len(bytes.TrimSpace(data)) == 0 || string(bytes.TrimSpace(data)) == "null"
+}
+
+// fieldName turns a Go field name like ReturnValue into returnValue.
+func fieldName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem().Name()
+	}
+	return t.Name()
+}
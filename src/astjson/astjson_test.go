@@ -0,0 +1,150 @@
+package astjson
+
+import (
+	goast "go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
+)
+
+// TestRoundTripParserFixtures encodes and decodes every Monkey program
+// found among the string literals of the parser tests.
+func TestRoundTripParserFixtures(t *testing.T) {
+	fixtures := parserFixtures(t)
+	if len(fixtures) < 100 {
+		t.Fatalf("too few fixtures found in parser tests. got=%d", len(fixtures))
+	}
+
+	seen := map[string]bool{}
+	for _, input := range fixtures {
+		program := parser.New(lexer.New(input)).ParseProgram()
+
+		data, err := Marshal(program)
+		if err != nil {
+			t.Fatalf("Marshal(%q) failed: %s", input, err)
+		}
+
+		decoded, err := UnmarshalProgram(data)
+		if err != nil {
+			t.Fatalf("Unmarshal(%q) failed: %s", input, err)
+		}
+
+		if decoded.String() != program.String() {
+			t.Errorf("round trip of %q changed the program. expected=%q, got=%q", input, program.String(), decoded.String())
+		}
+
+		again, err := Marshal(decoded)
+		if err != nil {
+			t.Fatalf("Marshal(decoded %q) failed: %s", input, err)
+		}
+		if string(again) != string(data) {
+			t.Errorf("round trip of %q changed the encoding.\nexpected=%s\ngot=     %s", input, data, again)
+		}
+
+		ast.Inspect(program, func(node ast.Node) bool {
+			if node != nil {
+				seen[kindOf(node)] = true
+			}
+			return true
+		})
+	}
+
+	for kind := range allNodeKinds() {
+		if !seen[kind] {
+			t.Errorf("no parser fixture contains a %s", kind)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	program := parser.New(lexer.New("-a // x")).ParseProgram()
+
+	data, err := Marshal(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"kind":"Program","statements":[` +
+		`{"kind":"ExpressionStatement","token":{"type":"-","literal":"-"},"pos":{"line":1,"column":1},"expression":` +
+		`{"kind":"PrefixExpression","token":{"type":"-","literal":"-"},"pos":{"line":1,"column":1},"operator":"-","right":` +
+		`{"kind":"Identifier","token":{"type":"IDENT","literal":"a"},"pos":{"line":1,"column":2},"value":"a"}}}],` +
+		`"comments":[{"text":"// x","pos":{"line":1,"column":4}}]}`
+	if string(data) != expected {
+		t.Errorf("wrong encoding.\nexpected=%s\ngot=     %s", expected, data)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`null`, "astjson: no node in input"},
+		{`{"kind": "Nope"}`, `astjson: unknown kind "Nope"`},
+		{`{"statements": []}`, "astjson: missing kind"},
+		{
+			`{"kind": "Program", "statements": [{"kind": "Identifier"}]}`,
+			"astjson: Program.statements: [0]: cannot use Identifier as Statement",
+		},
+		{`{"kind": "MatchArm"}`, "astjson: MatchArm is not a node"},
+	}
+
+	for _, tt := range tests {
+		_, err := Unmarshal([]byte(tt.input))
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("Unmarshal(%s) wrong error. expected=%q, got=%v", tt.input, tt.expected, err)
+		}
+	}
+}
+
+// parserFixtures returns the string literals of the parser tests that
+// parse as Monkey programs without errors.
+func parserFixtures(t *testing.T) []string {
+	file, err := goparser.ParseFile(gotoken.NewFileSet(), "../parser/parser_test.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := []string{}
+	goast.Inspect(file, func(n goast.Node) bool {
+		lit, ok := n.(*goast.BasicLit)
+		if !ok || lit.Kind != gotoken.STRING {
+			return true
+		}
+
+		input, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+
+		p := parser.New(lexer.New(input))
+		program := p.ParseProgram()
+		if len(p.Errors()) == 0 && len(program.Statements) > 0 {
+			fixtures = append(fixtures, input)
+		}
+		return true
+	})
+
+	return fixtures
+}
+
+func allNodeKinds() map[string]bool {
+	all := map[string]bool{}
+	for kind := range kinds {
+		all[kind] = true
+	}
+	// Clauses are not nodes, and comments are not visited.
+	delete(all, "MatchArm")
+	delete(all, "StructMethod")
+	return all
+}
+
+func kindOf(node ast.Node) string {
+	return reflect.TypeOf(node).Elem().Name()
+}
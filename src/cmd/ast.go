@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/astjson"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/dot"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
+)
+
+// runAST implements `interpreter ast [file]`, printing the syntax tree of
+// the file, or of standard input, as JSON or, with -dot, as a Graphviz
+// graph.
+func runAST(args []string) int {
+	flags := flag.NewFlagSet("ast", flag.ExitOnError)
+	asDot := flags.Bool("dot", false, "print the tree as a DOT graph instead of JSON")
+	flags.Parse(args)
+
+	program, status := parseSource(flags.Arg(0))
+	if program == nil {
+		return status
+	}
+
+	if *asDot {
+		if err := dot.AST(os.Stdout, program); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	data, err := astjson.MarshalIndent(program, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	os.Stdout.Write(append(data, '\n'))
+
+	return 0
+}
+
+// parseSource parses the file at path, or standard input if path is
+// empty. It reports errors to standard error and returns a nil program
+// with the exit status then.
+func parseSource(path string) (*ast.Program, int) {
+	var source []byte
+	var err error
+	if path == "" {
+		source, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		source, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, 1
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		for _, msg := range p.Errors() {
+			fmt.Fprintln(os.Stderr, msg)
+		}
+		return nil, 1
+	}
+
+	return program, 0
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/printer"
+)
+
+// runFmt implements `interpreter fmt [-w | -d] [files]`. Without files it
+// formats standard input.
+func runFmt(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := flags.Bool("w", false, "write the result to the file instead of standard output")
+	diff := flags.Bool("d", false, "print a diff instead of the formatted source")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "fmt: cannot use -w with standard input")
+			return 2
+		}
+		source, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return formatSource("<stdin>", source, "", *diff)
+	}
+
+	status := 0
+	for _, path := range flags.Args() {
+		source, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+			continue
+		}
+
+		writeTo := ""
+		if *write {
+			writeTo = path
+		}
+		if formatSource(path, source, writeTo, *diff) != 0 {
+			status = 1
+		}
+	}
+	return status
+}
+
+// formatSource formats source read from name. The result is written back
+// to writeTo if it is set and printed to standard output otherwise, unless
+// diff asks for the changes instead.
+func formatSource(name string, source []byte, writeTo string, diff bool) int {
+	formatted, err := printer.Format(source)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s:\n%s\n", name, err)
+		return 1
+	}
+
+	if diff {
+		os.Stdout.Write(unifiedDiff(name, source, formatted))
+	}
+
+	if writeTo != "" {
+		if bytes.Equal(source, formatted) {
+			return 0
+		}
+		if err := ioutil.WriteFile(writeTo, formatted, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	if !diff {
+		os.Stdout.Write(formatted)
+	}
+	return 0
+}
@@ -1,14 +1,51 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io/fs"
+	"io/ioutil"
 	"os"
 	"os/user"
+	"path/filepath"
 
+	"github.com/g-hyoga/writing-interpreter-in-go/src/evaluator"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/prelude"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/repl"
 )
 
+var (
+	strict    = flag.Bool("strict", false, "raise errors on out-of-range indexes and missing hash keys")
+	noPrelude = flag.Bool("no-prelude", false, "start without the prelude standard library")
+)
+
 func main() {
+	flag.Parse()
+
+	switch flag.Arg(0) {
+	case "fmt":
+		os.Exit(runFmt(flag.Args()[1:]))
+	case "ast":
+		os.Exit(runAST(flag.Args()[1:]))
+	case "tokens":
+		os.Exit(runTokens(flag.Args()[1:]))
+	}
+
+	env, err := newEnvironment()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if flag.NArg() > 0 {
+		os.Exit(runFile(flag.Arg(0), env))
+	}
+
+	env.SetModuleLoader(newModuleLoader(os.DirFS(".")))
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
@@ -17,5 +54,70 @@ func main() {
 	fmt.Printf("Hello %s! This is the Monkey Programming language!\n", user.Username)
 	fmt.Printf("Feel free to type in commands\n")
 
-	repl.Start(os.Stdin, os.Stdout)
+	repl.Start(os.Stdin, os.Stdout, env)
+}
+
+// newEnvironment returns a root environment set up according to the
+// command-line flags.
+func newEnvironment() (*object.Environment, error) {
+	env := object.NewEnvironment()
+	env.SetStrictIndexing(*strict)
+
+	if !*noPrelude {
+		if err := prelude.Load(env); err != nil {
+			return nil, err
+		}
+	}
+
+	return env, nil
+}
+
+// newModuleLoader returns a loader for imports from fsys whose modules run
+// in environments set up like the root one.
+func newModuleLoader(fsys fs.FS) *object.ModuleLoader {
+	loader := object.NewModuleLoader(fsys)
+	loader.NewEnvironment = func() *object.Environment {
+		// The prelude already loaded into the root environment, so this
+		// cannot fail.
+		env, _ := newEnvironment()
+		return env
+	}
+	return loader
+}
+
+// runFile evaluates the script at path and returns the exit status. The
+// script may import files from its own directory and below.
+func runFile(path string, env *object.Environment) int {
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	env.SetModuleLoader(newModuleLoader(os.DirFS(filepath.Dir(path))))
+	env.SetFile(filepath.Base(path))
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		for _, msg := range p.Errors() {
+			fmt.Fprintln(os.Stderr, msg)
+		}
+		return 1
+	}
+
+	macroEnv := object.NewEnvironment()
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, macroErr := evaluator.ExpandMacros(program, macroEnv)
+	if macroErr != nil {
+		fmt.Fprintln(os.Stderr, macroErr.Inspect())
+		return 1
+	}
+
+	if evaluated := evaluator.Eval(expanded, env); evaluated != nil && evaluated.Type() == object.ERROR_OBJ {
+		fmt.Fprintln(os.Stderr, evaluated.Inspect())
+		return 1
+	}
+
+	return 0
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// runTokens implements `interpreter tokens [-format table|json] [file]`,
+// printing every token of the file, or of standard input, with its
+// position. It fails if any token is ILLEGAL.
+func runTokens(args []string) int {
+	flags := flag.NewFlagSet("tokens", flag.ExitOnError)
+	format := flags.String("format", "table", "output format: table, or json for one JSON object per line")
+	flags.Parse(args)
+
+	var print func(io.Writer, []token.Token) error
+	switch *format {
+	case "table":
+		print = printTokenTable
+	case "json":
+		print = printTokenJSON
+	default:
+		fmt.Fprintf(os.Stderr, "tokens: unknown format %q\n", *format)
+		return 2
+	}
+
+	name := flags.Arg(0)
+	var source []byte
+	var err error
+	if name == "" {
+		name = "<stdin>"
+		source, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		source, err = ioutil.ReadFile(name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	tokens := []token.Token{}
+	l := lexer.New(string(source))
+	for {
+		tok := l.NextToken()
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	if err := print(os.Stdout, tokens); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	status := 0
+	for _, tok := range tokens {
+		if tok.Type == token.ILLEGAL {
+			fmt.Fprintf(os.Stderr, "%s:%s: illegal token %q\n", name, tok.Pos, tok.Literal)
+			status = 1
+		}
+	}
+	return status
+}
+
+func printTokenTable(w io.Writer, tokens []token.Token) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "POS\tTYPE\tLITERAL")
+	for _, tok := range tokens {
+		fmt.Fprintf(tw, "%s\t%s\t%q\n", tok.Pos, tok.Type, tok.Literal)
+	}
+
+	return tw.Flush()
+}
+
+type jsonToken struct {
+	Type    token.TokenType `json:"type"`
+	Literal string          `json:"literal"`
+	Line    int             `json:"line"`
+	Column  int             `json:"column"`
+}
+
+func printTokenJSON(w io.Writer, tokens []token.Token) error {
+	enc := json.NewEncoder(w)
+	for _, tok := range tokens {
+		if err := enc.Encode(jsonToken{tok.Type, tok.Literal, tok.Pos.Line, tok.Pos.Column}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Package dot renders ASTs and environments as Graphviz DOT graphs, e.g.
+//
+//	interpreter ast -dot prog.mk | dot -Tsvg > prog.svg
+package dot
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+)
+
+// maxValueLen is the length in code points at which binding values are
+// cut off in environment graphs.
+const maxValueLen = 40
+
+// AST writes node and everything below it as a DOT digraph with one box
+// per node and edges from each node to its children, in source order.
+func AST(w io.Writer, node ast.Node) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph ast {")
+	fmt.Fprintln(bw, "\tordering=out;")
+	fmt.Fprintln(bw, "\tnode [shape=box, fontname=\"monospace\"];")
+
+	count := 0
+	var stack []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return false
+		}
+		id := "n" + strconv.Itoa(count)
+		count++
+		fmt.Fprintf(bw, "\t%s [label=%s];\n", id, quote(nodeLabel(n)))
+		if len(stack) > 0 {
+			fmt.Fprintf(bw, "\t%s -> %s;\n", stack[len(stack)-1], id)
+		}
+		stack = append(stack, id)
+		return true
+	})
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// nodeLabel is the kind of n followed by the detail that its children do
+// not show, such as an identifier's name or an operator.
+func nodeLabel(n ast.Node) string {
+	kind := reflect.TypeOf(n).Elem().Name()
+	detail := ""
+	switch n := n.(type) {
+	case *ast.Identifier:
+		detail = n.Value
+	case *ast.IntegerLiteral:
+		detail = strconv.FormatInt(n.Value, 10)
+	case *ast.StringLiteral:
+		detail = strconv.Quote(n.Value)
+	case *ast.Boolean:
+		detail = strconv.FormatBool(n.Value)
+	case *ast.PrefixExpression:
+		detail = n.Operator
+	case *ast.InfixExpression:
+		detail = n.Operator
+	case *ast.RangeExpression:
+		detail = n.Token.Literal
+	case *ast.OptionalIndexExpression:
+		detail = n.Token.Literal
+	case *ast.FunctionLiteral:
+		switch {
+		case n.IsGenerator:
+			detail = "fn*"
+		case n.IsArrow:
+			detail = "=>"
+		}
+	case *ast.TypePattern:
+		detail = n.TypeName
+	case *ast.ArrayPattern:
+		if n.Rest != nil {
+			detail = "..." + n.Rest.Value
+		}
+	}
+	if detail == "" {
+		return kind
+	}
+	return kind + "\n" + detail
+}
+
+// Environment writes env and every environment reachable from it as a DOT
+// digraph. Each scope is a table of its bindings; dashed edges lead to the
+// enclosing scope and solid edges lead from a function, macro or struct
+// binding to the environment it closes over.
+func Environment(w io.Writer, env *object.Environment) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph env {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	fmt.Fprintln(bw, "\tnode [shape=plaintext, fontname=\"monospace\"];")
+
+	ids := map[*object.Environment]string{}
+	queue := []*object.Environment{}
+	visit := func(e *object.Environment) string {
+		id, ok := ids[e]
+		if !ok {
+			id = "e" + strconv.Itoa(len(ids))
+			ids[e] = id
+			queue = append(queue, e)
+		}
+		return id
+	}
+
+	visit(env)
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		id := ids[e]
+
+		names := e.Names()
+		fmt.Fprintf(bw, "\t%s [label=<%s>];\n", id, scopeTable(id, e, names))
+		if outer := e.Outer(); outer != nil {
+			fmt.Fprintf(bw, "\t%s -> %s [style=dashed, label=\"outer\"];\n", id, visit(outer))
+		}
+		for i, name := range names {
+			val, _ := e.Get(name)
+			for _, captured := range closures(val) {
+				if captured == nil || captured == e {
+					continue
+				}
+				fmt.Fprintf(bw, "\t%s:b%d -> %s;\n", id, i, visit(captured))
+			}
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// scopeTable is the HTML-like label of a scope: a title row followed by one
+// row per binding, whose value cell is the port captures edges start at.
+func scopeTable(id string, e *object.Environment, names []string) string {
+	var b strings.Builder
+	b.WriteString(`<table border="0" cellborder="1" cellspacing="0">`)
+	fmt.Fprintf(&b, `<tr><td colspan="2" bgcolor="lightgrey"><b>scope %s</b></td></tr>`, strings.TrimPrefix(id, "e"))
+	if len(names) == 0 {
+		b.WriteString(`<tr><td colspan="2"><i>empty</i></td></tr>`)
+	}
+	for i, name := range names {
+		val, _ := e.Get(name)
+		fmt.Fprintf(&b, `<tr><td align="left">%s</td><td align="left" port="b%d">%s</td></tr>`,
+			html.EscapeString(name), i, html.EscapeString(summary(val)))
+	}
+	b.WriteString(`</table>`)
+	return b.String()
+}
+
+// summary is a one-line description of val short enough for a table cell.
+// Functions are shown by their signature only, since their bodies would
+// swamp the graph.
+func summary(val object.Object) string {
+	var s string
+	switch val := val.(type) {
+	case nil:
+		return "<nil>"
+	case *object.Function:
+		s = signature("fn", val.IsGenerator, val.Parameters)
+	case *object.Macro:
+		s = signature("macro", false, val.Parameters)
+	default:
+		s = val.Inspect()
+	}
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + " ..."
+	}
+	if r := []rune(s); len(r) > maxValueLen {
+		s = string(r[:maxValueLen-3]) + "..."
+	}
+	return s
+}
+
+func signature(keyword string, generator bool, params []*ast.Identifier) string {
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Value
+	}
+	if generator {
+		keyword += "*"
+	}
+	return keyword + "(" + strings.Join(names, ", ") + ")"
+}
+
+// closures returns the environments val keeps alive: the defining
+// environment of a function or macro, and those of a struct's methods.
+func closures(val object.Object) []*object.Environment {
+	switch val := val.(type) {
+	case *object.Function:
+		return []*object.Environment{val.Env}
+	case *object.Macro:
+		return []*object.Environment{val.Env}
+	case *object.BoundMethod:
+		return []*object.Environment{val.Method.Env}
+	case *object.Composition:
+		return append(closures(val.First), closures(val.Second)...)
+	case *object.Struct:
+		names := make([]string, 0, len(val.Methods))
+		for name := range val.Methods {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		envs := []*object.Environment{}
+		for _, name := range names {
+			envs = append(envs, val.Methods[name].Env)
+		}
+		return envs
+	}
+	return nil
+}
+
+// quote returns s as a DOT string literal. Newlines become \n, which
+// Graphviz renders as a centred line break.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package dot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/evaluator"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
+)
+
+func TestAST(t *testing.T) {
+	input := `let add = fn(x, y) { x + y; }; add(1, "two");`
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	var out bytes.Buffer
+	if err := AST(&out, program); err != nil {
+		t.Fatalf("AST returned error: %s", err)
+	}
+	got := out.String()
+
+	expected := []string{
+		"digraph ast {\n",
+		"\tn0 [label=\"Program\"];\n",
+		"\tn1 [label=\"LetStatement\"];\n\tn0 -> n1;\n",
+		"\tn2 [label=\"Identifier\\nadd\"];\n\tn1 -> n2;\n",
+		"\tn3 [label=\"FunctionLiteral\"];\n\tn1 -> n3;\n",
+		"[label=\"InfixExpression\\n+\"];\n",
+		"[label=\"IntegerLiteral\\n1\"];\n",
+		"[label=\"StringLiteral\\n\\\"two\\\"\"];\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q. got=\n%s", want, got)
+		}
+	}
+	if !strings.HasSuffix(got, "}\n") {
+		t.Errorf("output is not a closed graph. got=\n%s", got)
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	input := `
+let makeAdder = fn(x) { fn(y) { x + y } };
+let addTwo = makeAdder(2);
+let name = "<b>";
+let accents = "ééééééééééééééééééééééééééééééééééééééééé";
+`
+	env := object.NewEnvironment()
+	evaluator.Eval(parser.New(lexer.New(input)).ParseProgram(), env)
+
+	var out bytes.Buffer
+	if err := Environment(&out, env); err != nil {
+		t.Fatalf("Environment returned error: %s", err)
+	}
+	got := out.String()
+
+	expected := []string{
+		"digraph env {\n",
+		"<b>scope 0</b>",
+		`<td align="left">accents</td><td align="left" port="b0">` + strings.Repeat("é", 37) + `...</td>`,
+		`<td align="left">addTwo</td><td align="left" port="b1">fn(y)</td>`,
+		`<td align="left">makeAdder</td><td align="left" port="b2">fn(x)</td>`,
+		`<td align="left">name</td><td align="left" port="b3">&lt;b&gt;</td>`,
+		// addTwo closes over the scope of the makeAdder call, which holds x
+		// and is enclosed by the top-level scope.
+		"\te0:b1 -> e1;\n",
+		`<td align="left">x</td><td align="left" port="b0">2</td>`,
+		"\te1 -> e0 [style=dashed, label=\"outer\"];\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q. got=\n%s", want, got)
+		}
+	}
+	if !utf8.ValidString(got) {
+		t.Errorf("output is not valid UTF-8. got=\n%s", got)
+	}
+	// makeAdder was defined in the top-level scope itself.
+	if strings.Contains(got, "e0:b2 ->") {
+		t.Errorf("output has an edge for a function closing over its own scope. got=\n%s", got)
+	}
+}
@@ -0,0 +1,485 @@
+package evaluator
+
+import (
+	"sort"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+)
+
+// The collection builtins call back into applyFunction, which reaches the
+// builtins table through evalIdentifier, so they are registered at init time
+// to avoid an initialization cycle.
+func init() {
+	builtins["map"] = &object.Builtin{Fn: builtinMap}
+	builtins["filter"] = &object.Builtin{Fn: builtinFilter}
+	builtins["reduce"] = &object.Builtin{Fn: builtinReduce}
+	builtins["sort"] = &object.Builtin{Fn: builtinSort}
+	builtins["any"] = &object.Builtin{Fn: builtinAny}
+	builtins["all"] = &object.Builtin{Fn: builtinAll}
+	builtins["find"] = &object.Builtin{Fn: builtinFind}
+	builtins["reverse"] = &object.Builtin{Fn: builtinReverse}
+	builtins["zip"] = &object.Builtin{Fn: builtinZip}
+	builtins["enumerate"] = &object.Builtin{Fn: builtinEnumerate}
+	builtins["flatten"] = &object.Builtin{Fn: builtinFlatten}
+	builtins["uniq"] = &object.Builtin{Fn: builtinUniq}
+	builtins["range"] = &object.Builtin{Fn: builtinRange}
+	builtins["take"] = &object.Builtin{Fn: builtinTake}
+	builtins["next"] = &object.Builtin{Fn: builtinNext}
+}
+
+func builtinMap(args ...object.Object) object.Object {
+	it, fn, err := iterableCallbackArguments("map", args)
+	if err != nil {
+		return err
+	}
+
+	elements := []object.Object{}
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return el
+		}
+		result := applyFunction(fn, []object.Object{el})
+		if isError(result) {
+			return result
+		}
+		elements = append(elements, result)
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinFilter(args ...object.Object) object.Object {
+	it, fn, err := iterableCallbackArguments("filter", args)
+	if err != nil {
+		return err
+	}
+
+	elements := []object.Object{}
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return el
+		}
+		keep, err := applyPredicate("filter", fn, el)
+		if err != nil {
+			return err
+		}
+		if keep {
+			elements = append(elements, el)
+		}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinReduce(args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=3", len(args))
+	}
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "first argument to `reduce` must be ITERABLE, got %s", args[0].Type())
+	}
+	if !isCallable(args[2]) {
+		return newTypedError(object.TYPE_ERROR, "third argument to `reduce` must be FUNCTION, got %s", args[2].Type())
+	}
+
+	acc := args[1]
+	it := iterable.Iterate()
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return el
+		}
+		acc = applyFunction(args[2], []object.Object{acc, el})
+		if isError(acc) {
+			return acc
+		}
+	}
+
+	return acc
+}
+
+// builtinSort implements sort(xs) and sort(xs, less). The result is a new
+// array ordered by <, or by less(a, b), which must return true exactly when
+// a belongs before b. Anything but a BOOLEAN from less is a TypeError, so
+// that a comparator in the a - b style fails loudly instead of silently
+// producing an arbitrary order. Equal elements keep their relative order.
+func builtinSort(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1 or 2", len(args))
+	}
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "first argument to `sort` must be ITERABLE, got %s", args[0].Type())
+	}
+	if len(args) == 2 && !isCallable(args[1]) {
+		return newTypedError(object.TYPE_ERROR, "second argument to `sort` must be FUNCTION, got %s", args[1].Type())
+	}
+
+	elements, err := collectElements(iterable)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(elements, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+
+		var result object.Object
+		if len(args) == 2 {
+			result = applyFunction(args[1], []object.Object{elements[i], elements[j]})
+		} else {
+			result = evalInfixExpression("<", elements[i], elements[j])
+		}
+
+		if isError(result) {
+			err = result
+			return false
+		}
+		less, ok := result.(*object.Boolean)
+		if !ok {
+			err = newTypedError(object.TYPE_ERROR, "comparator of `sort` must return BOOLEAN, got %s", typeOf(result))
+			return false
+		}
+		return less.Value
+	})
+	// sort.SliceStable cannot be stopped early, so the first error is
+	// reported once it returns.
+	if err != nil {
+		return err
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinAny(args ...object.Object) object.Object {
+	it, fn, err := iterableCallbackArguments("any", args)
+	if err != nil {
+		return err
+	}
+
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return el
+		}
+		found, err := applyPredicate("any", fn, el)
+		if err != nil {
+			return err
+		}
+		if found {
+			return TRUE
+		}
+	}
+
+	return FALSE
+}
+
+func builtinAll(args ...object.Object) object.Object {
+	it, fn, err := iterableCallbackArguments("all", args)
+	if err != nil {
+		return err
+	}
+
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return el
+		}
+		holds, err := applyPredicate("all", fn, el)
+		if err != nil {
+			return err
+		}
+		if !holds {
+			return FALSE
+		}
+	}
+
+	return TRUE
+}
+
+func builtinFind(args ...object.Object) object.Object {
+	it, fn, err := iterableCallbackArguments("find", args)
+	if err != nil {
+		return err
+	}
+
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return el
+		}
+		found, err := applyPredicate("find", fn, el)
+		if err != nil {
+			return err
+		}
+		if found {
+			return el
+		}
+	}
+
+	return NULL
+}
+
+func builtinReverse(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "argument to `reverse` must be ITERABLE, got %s", args[0].Type())
+	}
+
+	elements, err := collectElements(iterable)
+	if err != nil {
+		return err
+	}
+
+	for i, j := 0, len(elements)-1; i < j; i, j = i+1, j-1 {
+		elements[i], elements[j] = elements[j], elements[i]
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinZip(args ...object.Object) object.Object {
+	if len(args) < 2 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want>=2", len(args))
+	}
+
+	iterators := make([]object.Iterator, len(args))
+	for i, arg := range args {
+		iterable, ok := arg.(object.Iterable)
+		if !ok {
+			return newTypedError(object.TYPE_ERROR, "arguments to `zip` must be ITERABLE, got %s", arg.Type())
+		}
+		iterators[i] = iterable.Iterate()
+	}
+
+	elements := []object.Object{}
+	for {
+		tuple := make([]object.Object, len(iterators))
+		for i, it := range iterators {
+			el, ok := it.Next()
+			if !ok {
+				return &object.Array{Elements: elements}
+			}
+			if isError(el) {
+				return el
+			}
+			tuple[i] = el
+		}
+		elements = append(elements, &object.Array{Elements: tuple})
+	}
+}
+
+func builtinEnumerate(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "argument to `enumerate` must be ITERABLE, got %s", args[0].Type())
+	}
+
+	elements := []object.Object{}
+	it := iterable.Iterate()
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return el
+		}
+		pair := []object.Object{&object.Integer{Value: int64(len(elements))}, el}
+		elements = append(elements, &object.Array{Elements: pair})
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinFlatten(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "argument to `flatten` must be ITERABLE, got %s", args[0].Type())
+	}
+
+	elements := []object.Object{}
+	it := iterable.Iterate()
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return el
+		}
+		if inner, ok := el.(*object.Array); ok {
+			elements = append(elements, inner.Elements...)
+		} else {
+			elements = append(elements, el)
+		}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinUniq(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "argument to `uniq` must be ITERABLE, got %s", args[0].Type())
+	}
+
+	seen := make(map[object.HashKey]bool)
+	elements := []object.Object{}
+
+	it := iterable.Iterate()
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return el
+		}
+		if key, ok := el.(object.Hashable); ok {
+			if seen[key.HashKey()] {
+				continue
+			}
+			seen[key.HashKey()] = true
+		} else if containsObject(elements, el) {
+			continue
+		}
+		elements = append(elements, el)
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// builtinRange implements range(end), range(start, end) and
+// range(start, end, step). Like start..<end step step, it returns a lazy
+// range rather than an array.
+func builtinRange(args ...object.Object) object.Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1 to 3", len(args))
+	}
+
+	bounds := make([]int64, len(args))
+	for i, arg := range args {
+		integer, ok := arg.(*object.Integer)
+		if !ok {
+			return newTypedError(object.TYPE_ERROR, "arguments to `range` must be INTEGER, got %s", arg.Type())
+		}
+		bounds[i] = integer.Value
+	}
+
+	var start, end, step int64 = 0, bounds[0], 1
+	if len(bounds) > 1 {
+		start, end = bounds[0], bounds[1]
+	}
+	if len(bounds) > 2 {
+		step = bounds[2]
+	}
+	if step == 0 {
+		return newTypedError(object.VALUE_ERROR, "step of `range` must not be 0")
+	}
+
+	return &object.Range{Start: start, End: end, Step: step}
+}
+
+func builtinTake(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
+	}
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "first argument to `take` must be ITERABLE, got %s", args[0].Type())
+	}
+	count, ok := args[1].(*object.Integer)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "second argument to `take` must be INTEGER, got %s", args[1].Type())
+	}
+
+	elements := []object.Object{}
+	it := iterable.Iterate()
+	for int64(len(elements)) < count.Value {
+		el, ok := it.Next()
+		if !ok {
+			break
+		}
+		if isError(el) {
+			return el
+		}
+		elements = append(elements, el)
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinNext(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	if args[0].Type() != object.GENERATOR_OBJ {
+		return newTypedError(object.TYPE_ERROR, "argument to `next` must be GENERATOR, got %s", args[0].Type())
+	}
+
+	el, ok := args[0].(*object.Generator).Next()
+	if !ok {
+		return NULL
+	}
+	return el
+}
+
+// applyPredicate calls the callback fn of the builtin name with el. Like
+// the comparator of `sort`, it has to return a BOOLEAN.
+func applyPredicate(name string, fn, el object.Object) (bool, object.Object) {
+	result := applyFunction(fn, []object.Object{el})
+	if isError(result) {
+		return false, result
+	}
+	b, ok := result.(*object.Boolean)
+	if !ok {
+		return false, newTypedError(object.TYPE_ERROR, "predicate of `%s` must return BOOLEAN, got %s", name, typeOf(result))
+	}
+	return b.Value, nil
+}
+
+func iterableCallbackArguments(name string, args []object.Object) (object.Iterator, object.Object, *object.Error) {
+	if len(args) != 2 {
+		return nil, nil, newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
+	}
+	iterable, ok := args[0].(object.Iterable)
+	if !ok {
+		return nil, nil, newTypedError(object.TYPE_ERROR, "first argument to `%s` must be ITERABLE, got %s", name, args[0].Type())
+	}
+	if !isCallable(args[1]) {
+		return nil, nil, newTypedError(object.TYPE_ERROR, "second argument to `%s` must be FUNCTION, got %s", name, args[1].Type())
+	}
+
+	return iterable.Iterate(), args[1], nil
+}
+
+// collectElements drains an iterable into a fresh slice that the caller may
+// reorder freely.
+func collectElements(iterable object.Iterable) ([]object.Object, object.Object) {
+	elements := []object.Object{}
+	it := iterable.Iterate()
+	for el, ok := it.Next(); ok; el, ok = it.Next() {
+		if isError(el) {
+			return nil, el
+		}
+		elements = append(elements, el)
+	}
+	return elements, nil
+}
+
+func isCallable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin, *object.Composition, *object.Struct, *object.BoundMethod:
+		return true
+	default:
+		return false
+	}
+}
+
+func containsObject(elements []object.Object, obj object.Object) bool {
+	for _, el := range elements {
+		if object.Equal(el, obj) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package evaluator
 
 import (
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
 )
@@ -12,28 +13,56 @@ var builtins = map[string]*object.Builtin{
 	"rest":  &object.Builtin{Fn: builtinRest},
 	"push":  &object.Builtin{Fn: builtinPush},
 	"puts":  &object.Builtin{Fn: builtinPuts},
+	"is":    &object.Builtin{Fn: builtinIs},
+
+	"split":      &object.Builtin{Fn: builtinSplit},
+	"join":       &object.Builtin{Fn: builtinJoin},
+	"trim":       &object.Builtin{Fn: builtinTrim},
+	"upper":      &object.Builtin{Fn: builtinUpper},
+	"lower":      &object.Builtin{Fn: builtinLower},
+	"replace":    &object.Builtin{Fn: builtinReplace},
+	"contains":   &object.Builtin{Fn: builtinContains},
+	"startsWith": &object.Builtin{Fn: builtinStartsWith},
+	"endsWith":   &object.Builtin{Fn: builtinEndsWith},
+	"indexOf":    &object.Builtin{Fn: builtinIndexOf},
+	"substr":     &object.Builtin{Fn: builtinSubstr},
+	"chars":      &object.Builtin{Fn: builtinChars},
+	"repeat":     &object.Builtin{Fn: builtinRepeat},
+	"format":     &object.Builtin{Fn: builtinFormat},
+
+	"keys":   &object.Builtin{Fn: builtinKeys},
+	"values": &object.Builtin{Fn: builtinValues},
+	"items":  &object.Builtin{Fn: builtinItems},
+	"has":    &object.Builtin{Fn: builtinHas},
+	"get":    &object.Builtin{Fn: builtinGet},
+	"delete": &object.Builtin{Fn: builtinDelete},
+	"merge":  &object.Builtin{Fn: builtinMerge},
 }
 
 func builtinLen(args ...object.Object) object.Object {
 	if len(args) != 1 {
-		return newError("wrong number of arguments. got=%d, want=1", len(args))
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
 	}
 	switch arg := args[0].(type) {
 	case *object.String:
-		return &object.Integer{Value: int64(len(arg.Value))}
+		return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 	case *object.Array:
 		return &object.Integer{Value: int64(len(arg.Elements))}
+	case *object.Hash:
+		return &object.Integer{Value: int64(len(arg.Pairs))}
+	case *object.Range:
+		return &object.Integer{Value: arg.Len()}
 	default:
-		return newError("argument to `len` not supported, got %s", args[0].Type())
+		return newTypedError(object.TYPE_ERROR, "argument to `len` not supported, got %s", args[0].Type())
 	}
 }
 
 func builtinFirst(args ...object.Object) object.Object {
 	if len(args) != 1 {
-		return newError("wrong number of arguments. got=%d, want=1", len(args))
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
 	}
 	if args[0].Type() != object.ARRAY_OBJ {
-		return newError("argument to `first` must be ARRAY, got %s", args[0].Type())
+		return newTypedError(object.TYPE_ERROR, "argument to `first` must be ARRAY, got %s", args[0].Type())
 	}
 
 	arr := args[0].(*object.Array)
@@ -46,10 +75,10 @@ func builtinFirst(args ...object.Object) object.Object {
 
 func builtinRest(args ...object.Object) object.Object {
 	if len(args) != 1 {
-		return newError("wrong number of arguments. got=%d, want=1", len(args))
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
 	}
 	if args[0].Type() != object.ARRAY_OBJ {
-		return newError("argument to `rest` must be ARRAY, got %s", args[0].Type())
+		return newTypedError(object.TYPE_ERROR, "argument to `rest` must be ARRAY, got %s", args[0].Type())
 	}
 
 	arr := args[0].(*object.Array)
@@ -65,10 +94,10 @@ func builtinRest(args ...object.Object) object.Object {
 
 func builtinPush(args ...object.Object) object.Object {
 	if len(args) != 2 {
-		return newError("wrong number of arguments. got=%d, want=2", len(args))
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
 	}
 	if args[0].Type() != object.ARRAY_OBJ {
-		return newError("arguments to `push` must be ARRAY, got %s", args[0].Type())
+		return newTypedError(object.TYPE_ERROR, "arguments to `push` must be ARRAY, got %s", args[0].Type())
 	}
 
 	arr := args[0].(*object.Array)
@@ -88,3 +117,11 @@ func builtinPuts(args ...object.Object) object.Object {
 
 	return NULL
 }
+
+func builtinIs(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
+	}
+
+	return nativeBoolToBooleanObject(args[0] == args[1])
+}
@@ -0,0 +1,149 @@
+package evaluator
+
+import (
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+)
+
+func builtinKeys(args ...object.Object) object.Object {
+	hash, err := hashArgument("keys", args)
+	if err != nil {
+		return err
+	}
+
+	pairs := hash.SortedPairs()
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = pair.Key
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinValues(args ...object.Object) object.Object {
+	hash, err := hashArgument("values", args)
+	if err != nil {
+		return err
+	}
+
+	pairs := hash.SortedPairs()
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = pair.Value
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinItems(args ...object.Object) object.Object {
+	hash, err := hashArgument("items", args)
+	if err != nil {
+		return err
+	}
+
+	pairs := hash.SortedPairs()
+	elements := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func builtinHas(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.HASH_OBJ {
+		return newTypedError(object.TYPE_ERROR, "first argument to `has` must be HASH, got %s", args[0].Type())
+	}
+
+	key, ok := args[1].(object.Hashable)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "unusable as hash key: %s", args[1].Type())
+	}
+
+	_, ok = args[0].(*object.Hash).Pairs[key.HashKey()]
+	return nativeBoolToBooleanObject(ok)
+}
+
+func builtinGet(args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=3", len(args))
+	}
+
+	switch collection := args[0].(type) {
+	case *object.Hash:
+		key, ok := args[1].(object.Hashable)
+		if !ok {
+			return newTypedError(object.TYPE_ERROR, "unusable as hash key: %s", args[1].Type())
+		}
+		if pair, ok := collection.Pairs[key.HashKey()]; ok {
+			return pair.Value
+		}
+	case *object.Array:
+		index, ok := args[1].(*object.Integer)
+		if !ok {
+			return newTypedError(object.TYPE_ERROR, "second argument to `get` must be INTEGER, got %s", args[1].Type())
+		}
+		if idx, ok := normalizeIndex(index.Value, len(collection.Elements)); ok {
+			return collection.Elements[idx]
+		}
+	default:
+		return newTypedError(object.TYPE_ERROR, "first argument to `get` must be HASH or ARRAY, got %s", args[0].Type())
+	}
+
+	return args[2]
+}
+
+func builtinDelete(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.HASH_OBJ {
+		return newTypedError(object.TYPE_ERROR, "first argument to `delete` must be HASH, got %s", args[0].Type())
+	}
+
+	key, ok := args[1].(object.Hashable)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "unusable as hash key: %s", args[1].Type())
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	for hashKey, pair := range args[0].(*object.Hash).Pairs {
+		if hashKey != key.HashKey() {
+			pairs[hashKey] = pair
+		}
+	}
+
+	return &object.Hash{Pairs: pairs}
+}
+
+func builtinMerge(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want>=1", len(args))
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	for _, arg := range args {
+		hash, ok := arg.(*object.Hash)
+		if !ok {
+			return newTypedError(object.TYPE_ERROR, "arguments to `merge` must be HASH, got %s", arg.Type())
+		}
+		for hashKey, pair := range hash.Pairs {
+			pairs[hashKey] = pair
+		}
+	}
+
+	return &object.Hash{Pairs: pairs}
+}
+
+func hashArgument(name string, args []object.Object) (*object.Hash, *object.Error) {
+	if len(args) != 1 {
+		return nil, newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	if args[0].Type() != object.HASH_OBJ {
+		return nil, newTypedError(object.TYPE_ERROR, "argument to `%s` must be HASH, got %s", name, args[0].Type())
+	}
+
+	return args[0].(*object.Hash), nil
+}
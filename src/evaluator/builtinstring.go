@@ -0,0 +1,234 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+)
+
+func builtinSplit(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ || args[1].Type() != object.STRING_OBJ {
+		return newTypedError(object.TYPE_ERROR, "arguments to `split` must be STRING, got %s, %s", args[0].Type(), args[1].Type())
+	}
+
+	str := args[0].(*object.String).Value
+	sep := args[1].(*object.String).Value
+
+	return stringsToArray(strings.Split(str, sep))
+}
+
+func builtinJoin(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.ARRAY_OBJ {
+		return newTypedError(object.TYPE_ERROR, "first argument to `join` must be ARRAY, got %s", args[0].Type())
+	}
+	if args[1].Type() != object.STRING_OBJ {
+		return newTypedError(object.TYPE_ERROR, "second argument to `join` must be STRING, got %s", args[1].Type())
+	}
+
+	arr := args[0].(*object.Array)
+	sep := args[1].(*object.String).Value
+
+	elements := make([]string, len(arr.Elements))
+	for i, el := range arr.Elements {
+		str, ok := el.(*object.String)
+		if !ok {
+			return newTypedError(object.TYPE_ERROR, "elements of `join` must be STRING, got %s", el.Type())
+		}
+		elements[i] = str.Value
+	}
+
+	return &object.String{Value: strings.Join(elements, sep)}
+}
+
+func builtinTrim(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return newTypedError(object.TYPE_ERROR, "argument to `trim` must be STRING, got %s", args[0].Type())
+	}
+
+	return &object.String{Value: strings.TrimSpace(args[0].(*object.String).Value)}
+}
+
+func builtinUpper(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return newTypedError(object.TYPE_ERROR, "argument to `upper` must be STRING, got %s", args[0].Type())
+	}
+
+	return &object.String{Value: strings.ToUpper(args[0].(*object.String).Value)}
+}
+
+func builtinLower(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return newTypedError(object.TYPE_ERROR, "argument to `lower` must be STRING, got %s", args[0].Type())
+	}
+
+	return &object.String{Value: strings.ToLower(args[0].(*object.String).Value)}
+}
+
+func builtinReplace(args ...object.Object) object.Object {
+	if len(args) != 3 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=3", len(args))
+	}
+	for _, arg := range args {
+		if arg.Type() != object.STRING_OBJ {
+			return newTypedError(object.TYPE_ERROR, "arguments to `replace` must be STRING, got %s", arg.Type())
+		}
+	}
+
+	str := args[0].(*object.String).Value
+	old := args[1].(*object.String).Value
+	new := args[2].(*object.String).Value
+
+	return &object.String{Value: strings.Replace(str, old, new, -1)}
+}
+
+func builtinContains(args ...object.Object) object.Object {
+	str, sub, err := stringPairArguments("contains", args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.Contains(str, sub))
+}
+
+func builtinStartsWith(args ...object.Object) object.Object {
+	str, prefix, err := stringPairArguments("startsWith", args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasPrefix(str, prefix))
+}
+
+func builtinEndsWith(args ...object.Object) object.Object {
+	str, suffix, err := stringPairArguments("endsWith", args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasSuffix(str, suffix))
+}
+
+func builtinIndexOf(args ...object.Object) object.Object {
+	str, sub, err := stringPairArguments("indexOf", args)
+	if err != nil {
+		return err
+	}
+	idx := strings.Index(str, sub)
+	if idx < 0 {
+		return &object.Integer{Value: -1}
+	}
+	return &object.Integer{Value: int64(utf8.RuneCountInString(str[:idx]))}
+}
+
+func builtinSubstr(args ...object.Object) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2 or 3", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return newTypedError(object.TYPE_ERROR, "first argument to `substr` must be STRING, got %s", args[0].Type())
+	}
+	for _, arg := range args[1:] {
+		if arg.Type() != object.INTEGER_OBJ {
+			return newTypedError(object.TYPE_ERROR, "arguments to `substr` must be INTEGER, got %s", arg.Type())
+		}
+	}
+
+	runes := []rune(args[0].(*object.String).Value)
+	start := args[1].(*object.Integer).Value
+	end := int64(len(runes))
+	if len(args) == 3 {
+		end = start + args[2].(*object.Integer).Value
+	}
+
+	if start < 0 || start > int64(len(runes)) || end < start || end > int64(len(runes)) {
+		return newTypedError(object.INDEX_ERROR, "substr out of range: start=%d, end=%d, length=%d", start, end, len(runes))
+	}
+
+	return &object.String{Value: string(runes[start:end])}
+}
+
+func builtinChars(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return newTypedError(object.TYPE_ERROR, "argument to `chars` must be STRING, got %s", args[0].Type())
+	}
+
+	// Split with an empty separator cuts after each UTF-8 sequence, so the
+	// elements are the same characters that indexing returns.
+	return stringsToArray(strings.Split(args[0].(*object.String).Value, ""))
+}
+
+func builtinRepeat(args ...object.Object) object.Object {
+	if len(args) != 2 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return newTypedError(object.TYPE_ERROR, "first argument to `repeat` must be STRING, got %s", args[0].Type())
+	}
+	if args[1].Type() != object.INTEGER_OBJ {
+		return newTypedError(object.TYPE_ERROR, "second argument to `repeat` must be INTEGER, got %s", args[1].Type())
+	}
+
+	return evalStringRepeatExpression("*", args[0], args[1])
+}
+
+func builtinFormat(args ...object.Object) object.Object {
+	if len(args) < 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want>=1", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ {
+		return newTypedError(object.TYPE_ERROR, "first argument to `format` must be STRING, got %s", args[0].Type())
+	}
+
+	format := args[0].(*object.String).Value
+	values := make([]interface{}, len(args)-1)
+	for i, arg := range args[1:] {
+		switch arg := arg.(type) {
+		case *object.Integer:
+			values[i] = arg.Value
+		case *object.Boolean:
+			values[i] = arg.Value
+		case *object.String:
+			values[i] = arg.Value
+		default:
+			values[i] = arg.Inspect()
+		}
+	}
+
+	return &object.String{Value: fmt.Sprintf(format, values...)}
+}
+
+func stringPairArguments(name string, args []object.Object) (string, string, *object.Error) {
+	if len(args) != 2 {
+		return "", "", newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=2", len(args))
+	}
+	if args[0].Type() != object.STRING_OBJ || args[1].Type() != object.STRING_OBJ {
+		return "", "", newTypedError(object.TYPE_ERROR, "arguments to `%s` must be STRING, got %s, %s", name, args[0].Type(), args[1].Type())
+	}
+
+	return args[0].(*object.String).Value, args[1].(*object.String).Value, nil
+}
+
+func stringsToArray(strs []string) *object.Array {
+	elements := make([]object.Object, len(strs))
+	for i, s := range strs {
+		elements[i] = &object.String{Value: s}
+	}
+	return &object.Array{Elements: elements}
+}
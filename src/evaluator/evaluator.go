@@ -2,10 +2,13 @@ package evaluator
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/logger"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
 )
 
 var (
@@ -16,6 +19,12 @@ var (
 
 var log = logger.New()
 
+const maxInt = int(^uint(0) >> 1)
+
+// STRICT_PRAGMA turns on strict indexing for the rest of the program when it
+// appears as a string literal statement at the very top of a script.
+const STRICT_PRAGMA = "use strict"
+
 func Eval(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 
@@ -37,13 +46,37 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(val) {
 			return val
 		}
+		if node.Pattern != nil {
+			if err := destructure(node.Pattern, val, env, env); err != nil {
+				return err
+			}
+			return nil
+		}
 		env.Set(node.Name.Value, val)
+	case *ast.StructStatement:
+		env.Set(node.Name.Value, evalStructStatement(node))
+	case *ast.ImplStatement:
+		if err := evalImplStatement(node, env); err != nil {
+			return err
+		}
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+	case *ast.ExportStatement:
+		return Eval(node.Statement, env)
+	case *ast.ThrowStatement:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		return thrownError(val)
 
 	// Literal
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return &object.Function{Parameters: params, Patterns: node.Patterns, Env: env, Body: body, IsGenerator: node.IsGenerator}
+	case *ast.MacroLiteral:
+		return newError("macros must be defined with a top-level let")
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
 	case *ast.StringLiteral:
@@ -71,6 +104,15 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(left) {
 			return left
 		}
+		if node.Operator == "??" {
+			if left != NULL {
+				return left
+			}
+			return Eval(node.Right, env)
+		}
+		if node.Operator == "|>" {
+			return evalPipelineExpression(left, node.Right, env)
+		}
 		right := Eval(node.Right, env)
 		if isError(right) {
 			return right
@@ -80,26 +122,19 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalIfExpression(node, env)
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
-	case *ast.CallExpression:
-		function := Eval(node.Function, env)
-		if isError(function) {
-			return function
-		}
-		args := evalExpressions(node.Arguments, env)
-		if len(args) == 1 && isError(args[0]) {
-			return args[0]
-		}
-		return applyFunction(function, args)
-	case *ast.IndexExpression:
-		left := Eval(node.Left, env)
-		if isError(left) {
-			return left
-		}
-		index := Eval(node.Index, env)
-		if isError(index) {
-			return index
-		}
-		return evalIndexExpression(left, index)
+	case *ast.CallExpression, *ast.IndexExpression, *ast.MemberExpression, *ast.SliceExpression, *ast.OptionalIndexExpression:
+		result, _ := evalChainLink(node.(ast.Expression), env)
+		return result
+	case *ast.ConditionalExpression:
+		return evalConditionalExpression(node, env)
+	case *ast.RangeExpression:
+		return evalRangeExpression(node, env)
+	case *ast.YieldExpression:
+		return evalYieldExpression(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
 	}
 	return nil
 }
@@ -107,6 +142,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	var result object.Object
 
+	if hasStrictPragma(program) {
+		env.SetStrictIndexing(true)
+	}
+
 	for _, statement := range program.Statements {
 		result = Eval(statement, env)
 
@@ -125,6 +164,20 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	return result
 }
 
+func hasStrictPragma(program *ast.Program) bool {
+	if len(program.Statements) == 0 {
+		return false
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		return false
+	}
+
+	str, ok := stmt.Expression.(*ast.StringLiteral)
+	return ok && str.Value == STRICT_PRAGMA
+}
+
 func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
 	condition := Eval(ie.Condition, env)
 	if isError(condition) {
@@ -136,11 +189,22 @@ func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Obje
 	} else if ie.Alternative != nil {
 		return Eval(ie.Alternative, env)
 	} else {
-		log.Errorf("found unknown If structure. %#v", ie)
 		return NULL
 	}
 }
 
+func evalConditionalExpression(ce *ast.ConditionalExpression, env *object.Environment) object.Object {
+	condition := Eval(ce.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+
+	if isTruthy(condition) {
+		return Eval(ce.Consequence, env)
+	}
+	return Eval(ce.Alternative, env)
+}
+
 func isTruthy(obj object.Object) bool {
 	switch obj {
 	case NULL:
@@ -158,30 +222,130 @@ func isTruthy(obj object.Object) bool {
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	log.Debugf("[evaluator] call evalInfixExpression. operator: %s, left: %#v, right: %#v", operator, left, right)
 	switch {
+	case operator == "in":
+		return evalInExpression(left, right)
+	case operator == ">>":
+		return evalCompositionExpression(left, right)
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case left.Type() == object.STRING_OBJ && right.Type() == object.INTEGER_OBJ:
+		return evalStringRepeatExpression(operator, left, right)
 	case left.Type() != right.Type():
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		return newTypedError(object.TYPE_ERROR, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
+		return nativeBoolToBooleanObject(object.Equal(left, right))
 	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
+		return nativeBoolToBooleanObject(!object.Equal(left, right))
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
 	default:
 		log.Errorf("found unknown binary operands '%s', '%s'", left.Inspect(), right.Inspect())
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypedError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
-func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
-	if operator != "+" {
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+// evalPipelineExpression evaluates left |> right. When right is a call the
+// piped value becomes its first argument, so xs |> map(f) is map(xs, f);
+// any other callable is applied to the piped value alone.
+func evalPipelineExpression(left object.Object, right ast.Expression, env *object.Environment) object.Object {
+	call, ok := right.(*ast.CallExpression)
+	if !ok {
+		function := Eval(right, env)
+		if isError(function) {
+			return function
+		}
+		return applyFunction(function, []object.Object{left})
+	}
+
+	function := Eval(call.Function, env)
+	if isError(function) {
+		return function
+	}
+	args := evalExpressions(call.Arguments, env)
+	if len(args) == 1 && isError(args[0]) {
+		return args[0]
 	}
 
+	result := applyFunction(function, append([]object.Object{left}, args...))
+	if err, ok := result.(*object.Error); ok {
+		err.Stack = append(err.Stack, call.String())
+	}
+	return result
+}
+
+func evalCompositionExpression(left, right object.Object) object.Object {
+	if !isCallable(left) || !isCallable(right) {
+		return newTypedError(object.TYPE_ERROR, "operands of >> must be callable, got %s >> %s", left.Type(), right.Type())
+	}
+	return &object.Composition{First: left, Second: right}
+}
+
+func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
 	leftVal := left.(*object.String).Value
 	rightVal := right.(*object.String).Value
-	return &object.String{Value: leftVal + rightVal}
+
+	switch operator {
+	case "+":
+		return &object.String{Value: leftVal + rightVal}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	default:
+		return newTypedError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalStringRepeatExpression(operator string, left, right object.Object) object.Object {
+	if operator != "*" {
+		return newTypedError(object.TYPE_ERROR, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	}
+
+	leftVal := left.(*object.String).Value
+	count := right.(*object.Integer).Value
+	if count < 0 {
+		return newTypedError(object.VALUE_ERROR, "negative repeat count: %d", count)
+	}
+	// strings.Repeat panics when the result length overflows an int.
+	if len(leftVal) > 0 && count > int64(maxInt/len(leftVal)) {
+		return newTypedError(object.VALUE_ERROR, "repeat count too large: %d", count)
+	}
+
+	return &object.String{Value: strings.Repeat(leftVal, int(count))}
+}
+
+func evalInExpression(left, right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.String:
+		str, ok := left.(*object.String)
+		if !ok {
+			return newTypedError(object.TYPE_ERROR, "type mismatch: %s in %s", left.Type(), right.Type())
+		}
+		return nativeBoolToBooleanObject(strings.Contains(right.Value, str.Value))
+	case *object.Array:
+		for _, el := range right.Elements {
+			if object.Equal(left, el) {
+				return TRUE
+			}
+		}
+		return FALSE
+	case *object.Hash:
+		key, ok := left.(object.Hashable)
+		if !ok {
+			return newTypedError(object.TYPE_ERROR, "unusable as hash key: %s", left.Type())
+		}
+		_, ok = right.Pairs[key.HashKey()]
+		return nativeBoolToBooleanObject(ok)
+	case *object.Range:
+		integer, ok := left.(*object.Integer)
+		return nativeBoolToBooleanObject(ok && right.Contains(integer.Value))
+	default:
+		return newTypedError(object.TYPE_ERROR, "unknown operator: %s in %s", left.Type(), right.Type())
+	}
 }
 
 func evalIntegerInfixExpression(operator string, left, right object.Object) object.Object {
@@ -203,13 +367,17 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
 		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
 	case "==":
 		return nativeBoolToBooleanObject(leftVal == rightVal)
 	case "!=":
 		return nativeBoolToBooleanObject(leftVal != rightVal)
 	default:
 		log.Errorf("found unknown infix operator: '%s'", operator)
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypedError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -229,7 +397,7 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 func evalMinusPrefixExpression(right object.Object) object.Object {
 	if right.Type() != object.INTEGER_OBJ {
 		log.Errorf("found unkown token '%s' after '-'.", right.Inspect())
-		return newError("unknown operator: -%s", right.Type())
+		return newTypedError(object.TYPE_ERROR, "unknown operator: -%s", right.Type())
 	}
 
 	value := right.(*object.Integer).Value
@@ -244,49 +412,419 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 		return evalMinusPrefixExpression(right)
 	default:
 		log.Errorf("found unkown prefix '%s'", operator)
-		return newError("unknown operator: %s%s", operator, right.Type())
+		return newTypedError(object.TYPE_ERROR, "unknown operator: %s%s", operator, right.Type())
 	}
 }
 
-func evalIndexExpression(left, index object.Object) object.Object {
+func evalIndexExpression(left, index object.Object, strict bool) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
-		return evalArrayIndexExpression(left, index)
+		return evalArrayIndexExpression(left, index, strict)
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalStringIndexExpression(left, index, strict)
 	case left.Type() == object.HASH_OBJ:
-		return evalHashIndexExpression(left, index)
+		return evalHashIndexExpression(left, index, strict)
 	default:
-		return newError("index operator not supported: %s", left.Type())
+		return newTypedError(object.TYPE_ERROR, "index operator not supported: %s", left.Type())
 	}
 }
 
-func evalHashIndexExpression(hash, index object.Object) object.Object {
+func evalHashIndexExpression(hash, index object.Object, strict bool) object.Object {
 	hashObject := hash.(*object.Hash)
 
 	key, ok := index.(object.Hashable)
 	if !ok {
-		return newError("unusable as hash key: %s", index.Type())
+		return newTypedError(object.TYPE_ERROR, "unusable as hash key: %s", index.Type())
 	}
 
 	pair, ok := hashObject.Pairs[key.HashKey()]
 	if !ok {
+		if strict {
+			return newTypedError(object.KEY_ERROR, "key not found: %s", index.Inspect())
+		}
 		return NULL
 	}
 
 	return pair.Value
 }
 
-func evalArrayIndexExpression(array, index object.Object) object.Object {
+func evalArrayIndexExpression(array, index object.Object, strict bool) object.Object {
 	arrayObject := array.(*object.Array)
-	idx := index.(*object.Integer).Value
-	max := int64(len(arrayObject.Elements) - 1)
-
-	if idx < 0 || idx > max {
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(arrayObject.Elements))
+	if !ok {
+		if strict {
+			return indexOutOfRangeError(index, len(arrayObject.Elements))
+		}
 		return NULL
 	}
 
 	return arrayObject.Elements[idx]
 }
 
+// evalStringIndexExpression returns the character at index. Like len,
+// slicing, iteration and chars, indexing counts Unicode code points, not
+// bytes.
+func evalStringIndexExpression(str, index object.Object, strict bool) object.Object {
+	runes := []rune(str.(*object.String).Value)
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(runes))
+	if !ok {
+		if strict {
+			return indexOutOfRangeError(index, len(runes))
+		}
+		return NULL
+	}
+
+	return &object.String{Value: string(runes[idx])}
+}
+
+func indexOutOfRangeError(index object.Object, length int) *object.Error {
+	return newTypedError(object.INDEX_ERROR, "index out of range: index=%s, length=%d", index.Inspect(), length)
+}
+
+// normalizeIndex resolves a negative index from the end of a sequence
+// and reports whether the result lies within it.
+func normalizeIndex(idx int64, length int) (int64, bool) {
+	if idx < 0 {
+		idx += int64(length)
+	}
+	return idx, idx >= 0 && idx < int64(length)
+}
+
+// evalMemberExpression evaluates left.name, where leftNode is the
+// expression left was evaluated from. On an instance it looks up a field
+// first and then a method of the instance's struct; on a hash it reads the
+// string key name.
+func evalMemberExpression(leftNode ast.Expression, left object.Object, name string) object.Object {
+	switch left := left.(type) {
+	case *object.Module:
+		if value, ok := left.Exports[name]; ok {
+			return value
+		}
+		return newTypedError(object.KEY_ERROR, "module %s does not export %s", left.Path, name)
+	case *object.Hash:
+		key := &object.String{Value: name}
+		if pair, ok := left.Pairs[key.HashKey()]; ok {
+			return pair.Value
+		}
+		return newTypedError(object.KEY_ERROR, "key not found: %s in %s", name, memberPath(leftNode))
+	case *object.Instance:
+		if value, ok := left.Get(name); ok {
+			return value
+		}
+		if method, ok := left.Struct.Methods[name]; ok {
+			return &object.BoundMethod{Receiver: left, Name: name, Method: method}
+		}
+		return newTypedError(object.KEY_ERROR, "%s has no field or method %s", left.Struct.Name, name)
+	default:
+		return newTypedError(object.TYPE_ERROR, "member access not supported: %s", left.Type())
+	}
+}
+
+// evalChainLink evaluates an index, member access, call or slice, or a ?.
+// or ?[ link. cut reports that an optional link yielded null, which skips
+// the rest of the chain: every later link evaluates to null without
+// looking at its left side or arguments.
+func evalChainLink(node ast.Expression, env *object.Environment) (result object.Object, cut bool) {
+	switch node := node.(type) {
+	case *ast.OptionalIndexExpression:
+		left, cut := evalChainLink(node.Left, env)
+		if cut || isError(left) {
+			return left, cut
+		}
+		if left == NULL {
+			return NULL, true
+		}
+		if node.Token.Type == token.OPTIONAL_DOT && hasMembers(left) {
+			result := evalMemberExpression(node.Left, left, node.Index.(*ast.StringLiteral).Value)
+			return result, result == NULL
+		}
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return index, false
+		}
+		result := evalIndexExpression(left, index, false)
+		return result, result == NULL
+
+	case *ast.IndexExpression:
+		left, cut := evalChainOperand(node.Left, node.OptionalChain, env)
+		if cut || isError(left) {
+			return left, cut
+		}
+		index := Eval(node.Index, env)
+		if isError(index) {
+			return index, false
+		}
+		return evalIndexExpression(left, index, env.StrictIndexing()), false
+
+	case *ast.MemberExpression:
+		left, cut := evalChainOperand(node.Left, node.OptionalChain, env)
+		if cut || isError(left) {
+			return left, cut
+		}
+		return evalMemberExpression(node.Left, left, node.Member.Value), false
+
+	case *ast.SliceExpression:
+		left, cut := evalChainOperand(node.Left, node.OptionalChain, env)
+		if cut || isError(left) {
+			return left, cut
+		}
+		return evalSliceExpression(node, left, env), false
+
+	case *ast.CallExpression:
+		if isQuoteCall(node) {
+			return quote(node, env), false
+		}
+		function, cut := evalChainOperand(node.Function, node.OptionalChain, env)
+		if cut || isError(function) {
+			return function, cut
+		}
+		return evalCallExpression(node, function, env), false
+	}
+
+	return Eval(node, env), false
+}
+
+// memberPath renders node the way it was written when it is a name or a
+// chain of member accesses, such as cfg.db, for error messages. Other
+// expressions fall back to their String().
+func memberPath(node ast.Expression) string {
+	switch node := node.(type) {
+	case *ast.Identifier:
+		return node.Value
+	case *ast.MemberExpression:
+		return memberPath(node.Left) + "." + node.Member.Value
+	case *ast.OptionalIndexExpression:
+		if member, ok := node.Index.(*ast.StringLiteral); ok && node.Token.Type == token.OPTIONAL_DOT {
+			return memberPath(node.Left) + "?." + member.Value
+		}
+	}
+	return node.String()
+}
+
+// hasMembers reports whether left.name means something other than
+// left["name"], so that left?.name has to be evaluated as a member access.
+func hasMembers(left object.Object) bool {
+	switch left.(type) {
+	case *object.Instance, *object.Module:
+		return true
+	}
+	return false
+}
+
+// evalChainOperand evaluates the left side of a postfix expression. Only
+// when the expression continues an optional chain can the left side cut
+// it short.
+func evalChainOperand(left ast.Expression, optionalChain bool, env *object.Environment) (object.Object, bool) {
+	if !optionalChain {
+		return Eval(left, env), false
+	}
+	return evalChainLink(left, env)
+}
+
+func evalCallExpression(node *ast.CallExpression, function object.Object, env *object.Environment) object.Object {
+	args := evalExpressions(node.Arguments, env)
+	if len(args) == 1 && isError(args[0]) {
+		return args[0]
+	}
+	result := applyFunction(function, args)
+	if err, ok := result.(*object.Error); ok {
+		err.Stack = append(err.Stack, node.String())
+	}
+	return result
+}
+
+func evalSliceExpression(node *ast.SliceExpression, left object.Object, env *object.Environment) object.Object {
+	var length int
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.String:
+		length = utf8.RuneCountInString(left.Value)
+	default:
+		return newTypedError(object.TYPE_ERROR, "slice operator not supported: %s", left.Type())
+	}
+
+	start, err := evalSliceBound(node.Start, env, 0, length)
+	if err != nil {
+		return err
+	}
+	end, err := evalSliceBound(node.End, env, length, length)
+	if err != nil {
+		return err
+	}
+	if end < start {
+		end = start
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		elements := make([]object.Object, end-start)
+		copy(elements, left.Elements[start:end])
+		return &object.Array{Elements: elements}
+	default:
+		runes := []rune(left.(*object.String).Value)
+		return &object.String{Value: string(runes[start:end])}
+	}
+}
+
+// evalSliceBound evaluates one side of a slice, counting negative values
+// from the end and clamping the result to the bounds of the sequence.
+func evalSliceBound(node ast.Expression, env *object.Environment, def, length int) (int, object.Object) {
+	if node == nil {
+		return def, nil
+	}
+
+	bound := Eval(node, env)
+	if isError(bound) {
+		return 0, bound
+	}
+
+	integer, ok := bound.(*object.Integer)
+	if !ok {
+		return 0, newTypedError(object.TYPE_ERROR, "slice index must be INTEGER, got %s", bound.Type())
+	}
+
+	idx := integer.Value
+	if idx < 0 {
+		idx += int64(length)
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	if idx > int64(length) {
+		idx = int64(length)
+	}
+
+	return int(idx), nil
+}
+
+func evalRangeExpression(node *ast.RangeExpression, env *object.Environment) object.Object {
+	bounds := []ast.Expression{node.Start, node.End}
+	if node.Step != nil {
+		bounds = append(bounds, node.Step)
+	}
+
+	values := []int64{}
+	for _, bound := range bounds {
+		evaluated := Eval(bound, env)
+		if isError(evaluated) {
+			return evaluated
+		}
+		integer, ok := evaluated.(*object.Integer)
+		if !ok {
+			return newTypedError(object.TYPE_ERROR, "range bounds must be INTEGER, got %s", evaluated.Type())
+		}
+		values = append(values, integer.Value)
+	}
+
+	rng := &object.Range{Start: values[0], End: values[1], Step: 1, Inclusive: node.Inclusive}
+	if len(values) > 2 {
+		rng.Step = values[2]
+	}
+	if rng.Step == 0 {
+		return newTypedError(object.VALUE_ERROR, "range step must not be 0")
+	}
+
+	return rng
+}
+
+func evalYieldExpression(node *ast.YieldExpression, env *object.Environment) object.Object {
+	var val object.Object = NULL
+	if node.Value != nil {
+		val = Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+	}
+
+	if !env.Yield(val) {
+		return newError("yield outside generator function")
+	}
+	return NULL
+}
+
+// evalTryExpression runs each of the try, catch and finally blocks in a
+// scope of its own, so their bindings do not leak into the enclosing code.
+func evalTryExpression(node *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(node.Block, object.NewBlockEnvironment(env))
+
+	if err, ok := result.(*object.Error); ok && node.Catch != nil {
+		catchEnv := object.NewBlockEnvironment(env)
+		if node.CatchParam != nil {
+			catchEnv.Set(node.CatchParam.Value, errorToHash(err))
+		}
+		result = Eval(node.Catch, catchEnv)
+	}
+
+	if node.Finally != nil {
+		finally := Eval(node.Finally, object.NewBlockEnvironment(env))
+		if finally != nil {
+			rt := finally.Type()
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+				return finally
+			}
+		}
+	}
+
+	if result == nil {
+		return NULL
+	}
+	return result
+}
+
+// thrownError wraps a value given to throw. A hash may carry its own
+// "message" and "kind" entries; anything else becomes the message.
+func thrownError(val object.Object) *object.Error {
+	err := &object.Error{Kind: object.ERROR, Message: val.Inspect(), Value: val}
+
+	if hash, ok := val.(*object.Hash); ok {
+		if message, ok := hashStringValue(hash, "message"); ok {
+			err.Message = message
+		}
+		if kind, ok := hashStringValue(hash, "kind"); ok {
+			err.Kind = kind
+		}
+	}
+
+	return err
+}
+
+// errorToHash exposes a caught error to the catch block as a hash with
+// "message", "kind" and "stack" entries, plus the thrown "value" if any.
+func errorToHash(err *object.Error) *object.Hash {
+	stack := make([]object.Object, len(err.Stack))
+	for i, frame := range err.Stack {
+		stack[i] = &object.String{Value: frame}
+	}
+
+	pairs := make(map[object.HashKey]object.HashPair)
+	set := func(key string, value object.Object) {
+		k := &object.String{Value: key}
+		pairs[k.HashKey()] = object.HashPair{Key: k, Value: value}
+	}
+
+	set("message", &object.String{Value: err.Message})
+	set("kind", &object.String{Value: err.Kind})
+	set("stack", &object.Array{Elements: stack})
+	if err.Value != nil {
+		set("value", err.Value)
+	}
+
+	return &object.Hash{Pairs: pairs}
+}
+
+func hashStringValue(hash *object.Hash, key string) (string, bool) {
+	pair, ok := hash.Pairs[(&object.String{Value: key}).HashKey()]
+	if !ok {
+		return "", false
+	}
+	str, ok := pair.Value.(*object.String)
+	if !ok {
+		return "", false
+	}
+	return str.Value, true
+}
+
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
 	var result object.Object
 
@@ -312,7 +850,11 @@ func nativeBoolToBooleanObject(input bool) *object.Boolean {
 }
 
 func newError(format string, a ...interface{}) *object.Error {
-	return &object.Error{Message: fmt.Sprintf(format, a...)}
+	return newTypedError(object.ERROR, format, a...)
+}
+
+func newTypedError(kind, format string, a ...interface{}) *object.Error {
+	return &object.Error{Kind: kind, Message: fmt.Sprintf(format, a...)}
 }
 
 func isError(obj object.Object) bool {
@@ -330,7 +872,7 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 	if builtin, ok := builtins[node.Value]; ok {
 		return builtin
 	}
-	return newError("identifier not found: " + node.Value)
+	return newTypedError(object.NAME_ERROR, "identifier not found: %s", node.Value)
 }
 
 func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
@@ -349,24 +891,60 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 func applyFunction(fn object.Object, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		extendedEnv := extendFunctionEnv(fn, args)
+		if len(args) < len(fn.Parameters) {
+			return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=%d", len(args), len(fn.Parameters))
+		}
+		extendedEnv, err := extendFunctionEnv(fn, args)
+		if err != nil {
+			return err
+		}
+		if fn.IsGenerator {
+			return object.NewGenerator(fn, extendedEnv, func() object.Object {
+				return unwrapReturnValue(Eval(fn.Body, extendedEnv))
+			})
+		}
 		evaluated := Eval(fn.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 	case *object.Builtin:
 		return fn.Fn(args...)
+	case *object.Struct:
+		if len(args) != len(fn.Fields) {
+			return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments to %s. got=%d, want=%d", fn.Name, len(args), len(fn.Fields))
+		}
+		values := make([]object.Object, len(args))
+		copy(values, args)
+		return &object.Instance{Struct: fn, Values: values}
+	case *object.BoundMethod:
+		env := object.NewEnclosedEnvironment(fn.Method.Env)
+		env.Set("self", fn.Receiver)
+		method := *fn.Method
+		method.Env = env
+		return applyFunction(&method, args)
+	case *object.Composition:
+		result := applyFunction(fn.First, args)
+		if isError(result) {
+			return result
+		}
+		return applyFunction(fn.Second, []object.Object{result})
 	default:
-		return newError("not a function: %s", fn.Type())
+		return newTypedError(object.TYPE_ERROR, "not a function: %s", fn.Type())
 	}
 }
 
-func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
+func extendFunctionEnv(fn *object.Function, args []object.Object) (*object.Environment, *object.Error) {
 	env := object.NewEnclosedEnvironment(fn.Env)
 
 	for paramIdx, param := range fn.Parameters {
+		if paramIdx < len(fn.Patterns) && fn.Patterns[paramIdx] != nil {
+			if err := destructure(fn.Patterns[paramIdx], args[paramIdx], fn.Env, env); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		env.Set(param.Value, args[paramIdx])
 	}
 
-	return env
+	return env, nil
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -387,7 +965,7 @@ func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Obje
 
 		hashKey, ok := key.(object.Hashable)
 		if !ok {
-			return newError("unusable as hash key: %s", key.Type())
+			return newTypedError(object.TYPE_ERROR, "unusable as hash key: %s", key.Type())
 		}
 
 		value := Eval(valueNode, env)
@@ -2,7 +2,9 @@ package evaluator
 
 import (
 	"testing"
+	"testing/fstest"
 
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
@@ -138,6 +140,14 @@ func TestArrayIndexExpressions(t *testing.T) {
 		},
 		{
 			"[1, 2, 3][-1]",
+			3,
+		},
+		{
+			"[1, 2, 3][-3]",
+			1,
+		},
+		{
+			"[1, 2, 3][-4]",
 			nil,
 		},
 	}
@@ -153,6 +163,71 @@ func TestArrayIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3, 4][1:3]", "[2, 3]"},
+		{"[1, 2, 3, 4][:-1]", "[1, 2, 3]"},
+		{"[1, 2, 3, 4][2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][:]", "[1, 2, 3, 4]"},
+		{"[1, 2, 3, 4][-2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][3:1]", "[]"},
+		{"[1, 2, 3, 4][-10:10]", "[1, 2, 3, 4]"},
+		{`"hello"[0]`, "h"},
+		{`"hello"[-1]`, "o"},
+		{`"hello"[5]`, "null"},
+		{`"hello"[2:]`, "llo"},
+		{`"hello"[1:-1]`, "ell"},
+		{`let s = "hello"; s[:len(s) - 2]`, "hel"},
+		{`[1, 2]["a":]`, "ERROR: slice index must be INTEGER, got STRING"},
+		{`1[0:1]`, "ERROR: slice operator not supported: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStrictIndexing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"use strict"; [1, 2, 3][3]`, "ERROR: index out of range: index=3, length=3"},
+		{`"use strict"; [1, 2, 3][-4]`, "ERROR: index out of range: index=-4, length=3"},
+		{`"use strict"; [1, 2, 3][-1]`, "3"},
+		{`"use strict"; "abc"[5]`, "ERROR: index out of range: index=5, length=3"},
+		{`"use strict"; {"a": 1}["b"]`, "ERROR: key not found: b"},
+		{`"use strict"; let f = fn(h) { h[2] }; f({1: 1})`, "ERROR: key not found: 2"},
+		{`"use strict"; [1, 2, 3][5:]`, "[]"},
+		{`"use strict"; get({"a": 1}, "b", 0)`, "0"},
+		{`"use strict"; get({"a": 1}, "a", 0)`, "1"},
+		{`get([1, 2], 5, "none")`, "none"},
+		{`get([1, 2], -1, "none")`, "2"},
+		{`get(1, 1, 1)`, "ERROR: first argument to `get` must be HASH or ARRAY, got INTEGER"},
+		{`1; "use strict"; [1][1]`, "null"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+
+	env := object.NewEnvironment()
+	env.SetStrictIndexing(true)
+	program := parser.New(lexer.New("[1][1]")).ParseProgram()
+	if evaluated := Eval(program, env); !isError(evaluated) {
+		t.Errorf("strict environment did not raise an error. got=%T (%+v)", evaluated, evaluated)
+	}
+}
+
 func TestArrayLiteral(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 
@@ -201,6 +276,316 @@ func TestBuiltinFunction(t *testing.T) {
 	}
 }
 
+func TestStringBuiltinFunction(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`split("a,b,c", ",")`, "[a, b, c]"},
+		{`split("abc", "")`, "[a, b, c]"},
+		{`split(1, ",")`, "ERROR: arguments to `split` must be STRING, got INTEGER, STRING"},
+		{`join(["a", "b", "c"], "-")`, "a-b-c"},
+		{`join([], "-")`, ""},
+		{`join([1], "-")`, "ERROR: elements of `join` must be STRING, got INTEGER"},
+		{`join("a", "-")`, "ERROR: first argument to `join` must be ARRAY, got STRING"},
+		{`trim("  hello  ")`, "hello"},
+		{`upper("Hello")`, "HELLO"},
+		{`lower("Hello")`, "hello"},
+		{`upper(1)`, "ERROR: argument to `upper` must be STRING, got INTEGER"},
+		{`replace("a-b-c", "-", "+")`, "a+b+c"},
+		{`contains("hello", "ell")`, "true"},
+		{`contains("hello", "xyz")`, "false"},
+		{`startsWith("hello", "he")`, "true"},
+		{`endsWith("hello", "he")`, "false"},
+		{`indexOf("hello", "l")`, "2"},
+		{`indexOf("hello", "z")`, "-1"},
+		{`substr("hello", 1)`, "ello"},
+		{`substr("hello", 1, 3)`, "ell"},
+		{`substr("hello", 4, 3)`, "ERROR: substr out of range: start=4, end=7, length=5"},
+		{`substr("hello")`, "ERROR: wrong number of arguments. got=1, want=2 or 3"},
+		{`chars("abc")`, "[a, b, c]"},
+		{`chars("héllo")`, "[h, é, l, l, o]"},
+		{`let s = "héllo"; [len(s), len(chars(s)), s[1], s[-1], s[1:3], substr(s, 1, 2), indexOf(s, "l")]`, "[5, 5, é, o, él, él, 2]"},
+		{`map("日本", fn(c) { c })`, "[日, 本]"},
+		{`repeat("ab", 3)`, "ababab"},
+		{`repeat("ab", "3")`, "ERROR: second argument to `repeat` must be INTEGER, got STRING"},
+		{`repeat("ab", 4611686018427387904)`, "ERROR: repeat count too large: 4611686018427387904"},
+		{`format("%s is %d, %t", "x", 10, true)`, "x is 10, true"},
+		{`format("%5s|%-3d|", "ab", 7)`, "   ab|7  |"},
+		{`format("%s", [1, 2])`, "[1, 2]"},
+		{`format(1)`, "ERROR: first argument to `format` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestCollectionBuiltinFunction(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`map([1, 2, 3], fn(x) { x * 2 })`, "[2, 4, 6]"},
+		{`map([], fn(x) { x * 2 })`, "[]"},
+		{`map(["a", "b"], upper)`, "[A, B]"},
+		{`map([1], 1)`, "ERROR: second argument to `map` must be FUNCTION, got INTEGER"},
+		{`map(1, fn(x) { x })`, "ERROR: first argument to `map` must be ITERABLE, got INTEGER"},
+		{`map([1], fn(x, y) { x })`, "ERROR: wrong number of arguments. got=1, want=2"},
+		{`map([1, true], fn(x) { -x })`, "ERROR: unknown operator: -BOOLEAN"},
+		{`filter([1, 2, 3, 4], fn(x) { x > 2 })`, "[3, 4]"},
+		{`reduce([1, 2, 3, 4], 0, fn(acc, x) { acc + x })`, "10"},
+		{`reduce([], 5, fn(acc, x) { acc + x })`, "5"},
+		{`sort([3, 1, 2])`, "[1, 2, 3]"},
+		{`sort(["b", "c", "a"])`, "[a, b, c]"},
+		{`sort([3, 1, 2], fn(a, b) { a > b })`, "[3, 2, 1]"},
+		{`let a = [3, 1, 2]; sort(a); a`, "[3, 1, 2]"},
+		{`sort([true, false])`, "ERROR: unknown operator: BOOLEAN < BOOLEAN"},
+		{`sort([3, 1, 2], fn(a, b) { a - b })`, "ERROR: comparator of `sort` must return BOOLEAN, got INTEGER"},
+		{`sort([3, 1, 2], fn(a, b) { if (a == 1) { throw "bad" }; a < b })`, "ERROR: bad"},
+		{`sort([[2, "a"], [1, "b"], [2, "c"]], fn(a, b) { a[0] < b[0] })`, "[[1, b], [2, a], [2, c]]"},
+		{`reverse([1, 2, 3])`, "[3, 2, 1]"},
+		{`zip([1, 2, 3], ["a", "b"])`, "[[1, a], [2, b]]"},
+		{`zip([1], 2)`, "ERROR: arguments to `zip` must be ITERABLE, got INTEGER"},
+		{`enumerate(["a", "b"])`, "[[0, a], [1, b]]"},
+		{`flatten([1, [2, 3], [[4]]])`, "[1, 2, 3, [4]]"},
+		{`uniq([1, 2, 1, "a", "a", [1], [1]])`, "[1, 2, a, [1]]"},
+		{`any([1, 2, 3], fn(x) { x > 2 })`, "true"},
+		{`any([], fn(x) { x > 2 })`, "false"},
+		{`all([1, 2, 3], fn(x) { x > 0 })`, "true"},
+		{`all([1, 2, 3], fn(x) { x > 1 })`, "false"},
+		{`find([1, 2, 3], fn(x) { x > 1 })`, "2"},
+		{`find([1, 2, 3], fn(x) { x > 5 })`, "null"},
+		{`filter([1, 2], fn(x) { x })`, "ERROR: predicate of `filter` must return BOOLEAN, got INTEGER"},
+		{`any([1], fn(x) { "yes" })`, "ERROR: predicate of `any` must return BOOLEAN, got STRING"},
+		{`all([1], fn(x) { {}["x"] })`, "ERROR: predicate of `all` must return BOOLEAN, got NULL"},
+		{`find([1], fn(x) { len(1, 2) })`, "ERROR: wrong number of arguments. got=2, want=1"},
+		{`range(4)`, "0..<4"},
+		{`map(range(4), fn(x) { x })`, "[0, 1, 2, 3]"},
+		{`map(range(2, 5), fn(x) { x })`, "[2, 3, 4]"},
+		{`map(range(5, 0, -2), fn(x) { x })`, "[5, 3, 1]"},
+		{`map(range(9223372036854775800, 9223372036854775807, 5), fn(x) { x })`, "[9223372036854775800, 9223372036854775805]"},
+		{`map(range(-9223372036854775800, -9223372036854775807, -5), fn(x) { x })`, "[-9223372036854775800, -9223372036854775805]"},
+		{`len(range(-9223372036854775807, 9223372036854775807))`, "9223372036854775807"},
+		{`len(range(-9223372036854775807, 9223372036854775807, 4611686018427387904))`, "4"},
+		{`3 in range(-9223372036854775807, 9223372036854775807, 2)`, "true"},
+		{`map(9223372036854775806..9223372036854775807, fn(x) { x })`, "[9223372036854775806, 9223372036854775807]"},
+		{`range(0, 5, 0)`, "ERROR: step of `range` must not be 0"},
+		{`range("a")`, "ERROR: arguments to `range` must be INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestRangeExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0..3", "0..3"},
+		{"let n = 4; 0..<n", "0..<4"},
+		{"0..10 step 2", "0..10 step 2"},
+		{"map(0..3, fn(x) { x })", "[0, 1, 2, 3]"},
+		{"map(0..<3, fn(x) { x })", "[0, 1, 2]"},
+		{"map(0..10 step 3, fn(x) { x })", "[0, 3, 6, 9]"},
+		{"map(5..1 step -2, fn(x) { x })", "[5, 3, 1]"},
+		{"map(3..<3, fn(x) { x })", "[]"},
+		{"len(0..10 step 3)", "4"},
+		{"len(0..<9 step 3)", "3"},
+		{"len(5..1 step -2)", "3"},
+		{"len(3..<3)", "0"},
+		{"1 + 1..2 * 3", "2..6"},
+		{"4 in 0..10 step 2", "true"},
+		{"5 in 0..10 step 2", "false"},
+		{"10 in 0..<10", "false"},
+		{"0..3 == 0..3", "true"},
+		{"0..3 == 0..<3", "false"},
+		{"reduce(1..100000, 0, fn(acc, x) { acc + x })", "5000050000"},
+		{"find(1..1000000000, fn(x) { x * x > 50 })", "8"},
+		{`filter("hello", fn(c) { c != "l" })`, "[h, e, o]"},
+		{`map({"b": 1, "a": 2}, fn(k) { k })`, "[a, b]"},
+		{`zip(0..<2, ["a", "b", "c"])`, "[[0, a], [1, b]]"},
+		{"reverse(1..3)", "[3, 2, 1]"},
+		{"sort(3..1 step -1)", "[1, 2, 3]"},
+		{`0.."a"`, "ERROR: range bounds must be INTEGER, got STRING"},
+		{"0..10 step 0", "ERROR: range step must not be 0"},
+		{"let step = 3; let r = 0..9 step step; [step, len(r)]", "[3, 4]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let g = fn*() { yield 1; yield 2; }; map(g(), fn(x) { x * 10 })", "[10, 20]"},
+		{"let g = fn*(n) { yield n; yield n + 1; }; let it = g(5); [next(it), next(it), next(it)]", "[5, 6, null]"},
+		{`
+let countdown = fn*(n) {
+	if (n > 0) {
+		yield n;
+		yield n - 1;
+	}
+	return 99;
+	yield 0;
+};
+map(countdown(2), fn(x) { x })`, "[2, 1]"},
+		{"let g = fn*() { yield; }; map(g(), fn(x) { x })", "[null]"},
+		{"let g = fn*() { yield 1; yield -true; yield 3; }; map(g(), fn(x) { x })", "ERROR: unknown operator: -BOOLEAN"},
+		{"let g = fn*() { let f = fn(x) { yield x }; f(1) }; map(g(), fn(x) { x })", "ERROR: yield outside generator function"},
+		{"yield 1", "ERROR: yield outside generator function"},
+		{"let g = fn*() { yield 1; yield 2; }; let it = g(); map(it, fn(x) { x }); map(it, fn(x) { x })", "[]"},
+		{"let g = fn*(n) { yield n; yield n * 2; yield n * 3; }; find(g(2), fn(x) { x > 3 })", "4"},
+		{"let g = fn*() { yield 1; }; g()", "generator"},
+		{"next([1])", "ERROR: argument to `next` must be GENERATOR, got ARRAY"},
+		{"let g = fn*() { yield next(it); }; let it = g(); next(it)", "ERROR: generator already running"},
+		{"let g = fn*() { yield 1; map(it, fn(x) { x }); }; let it = g(); next(it); next(it)", "ERROR: generator already running"},
+		{"let g = fn*() { yield next(it); }; let it = g(); try { next(it) } catch (e) { e }; next(it)", "null"},
+		{"take(0..<1000000000, 3)", "[0, 1, 2]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (1) { 0 => "zero", 1 => "one", _ => "many" }`, "one"},
+		{`match (5) { 0 => "zero", 1 => "one", _ => "many" }`, "many"},
+		{`match ("b") { "a" => 1, "b" => 2 }`, "2"},
+		{`match (-1) { -1 => "minus one", _ => "other" }`, "minus one"},
+		{`let xs = [1, 3]; match (2) { a if any(xs, y => y > a) => "big", _ => "small" }`, "big"},
+		{`match (true) { false => 0, true => 1 }`, "1"},
+		{`match (7) { n => n * 2 }`, "14"},
+		{`match ([1, 2, 3]) { [] => "empty", [a] => a, [a, b, ...rest] => [a, b, rest] }`, "[1, 2, [3]]"},
+		{`match ([1]) { [] => "empty", [a] => a, [a, ...rest] => rest }`, "1"},
+		{`match ([1, 2]) { [a, ...rest] => rest }`, "[2]"},
+		{`match ([1, [2, 3]]) { [1, [x, y]] => x + y }`, "5"},
+		{`match ([1, 2]) { [_, 3] => "no", [_, 2] => "yes" }`, "yes"},
+		{`match ({"name": "ann", "age": 3}) { {"name": n, "age": 3} => n }`, "ann"},
+		{`match ({"name": "ann", "age": 3}) { {name, age: years} => [name, years] }`, "[ann, 3]"},
+		{`match ({"name": "ann"}) { {age} => age, {name} => name }`, "ann"},
+		{`match (1) { s: STRING => "string", n: INTEGER => n + 1 }`, "2"},
+		{`match ("a") { _: INTEGER => 1, _: STRING => 2 }`, "2"},
+		{`match ([1]) { _: HASH => 1, _: ARRAY => 2 }`, "2"},
+		{`match (5) { n if n > 10 => "big", n if n > 0 => "positive", _ => "other" }`, "positive"},
+		{`match (-5) { n if n > 0 => "positive", _ => n }`, "ERROR: identifier not found: n"},
+		{`match (2) { n => { let m = n * 10; m + 1 } }`, "21"},
+		{`let f = fn(x) { match (x) { 0 => { return "early" } _ => "late" }; "after" }; [f(0), f(1)]`, "[early, after]"},
+		{`match (3) { 1 => "one", 2 => "two" }`, "ERROR: non-exhaustive match: no arm matches 3"},
+		{`match ([1, 2]) { [a] => a }`, "ERROR: non-exhaustive match: no arm matches [1, 2]"},
+		{`try { match ("x") { 1 => 1 } } catch (e) { e["kind"] }`, "MatchError"},
+		{`match (1) { n: NUMBER => n }`, "ERROR: unknown type in pattern: NUMBER"},
+		{`match (1 + true) { _ => 1 }`, "ERROR: type mismatch: INTEGER + BOOLEAN"},
+		{`let x = 1; let y = match (5) { x => x * 2 }; [x, y]`, "[1, 10]"},
+		{`let g = fn*(xs) { match (xs) { [a, b] => { yield a; yield b } } }; map(g([1, 2]), fn(x) { x })`, "[1, 2]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { 1 } catch (e) { 2 }`, "1"},
+		{`try { throw "boom"; 1 } catch (e) { e["message"] }`, "boom"},
+		{`try { throw "boom" } catch (e) { e["kind"] + ": " + e["value"] }`, "Error: boom"},
+		{`try { throw {"message": "bad row", "kind": "ParseError"} } catch (e) { [e["kind"], e["message"]] }`, "[ParseError, bad row]"},
+		{`try { 1 + true } catch (e) { e["kind"] }`, "TypeError"},
+		{`try { len(1, 2) } catch (e) { e["kind"] }`, "ArgumentError"},
+		{`try { foo } catch (e) { [e["kind"], e["message"]] }`, "[NameError, identifier not found: foo]"},
+		{`"use strict"; try { [1][5] } catch (e) { e["kind"] }`, "IndexError"},
+		{`"use strict"; try { {}["a"] } catch (e) { e["kind"] }`, "KeyError"},
+		{`try { 1 + true } catch { "caught" }`, "caught"},
+		{`try { 1 + true } catch (e) { has(e, "value") }`, "false"},
+		{`
+let inner = fn(x) { x + true };
+let outer = fn(x) { inner(x) };
+try { outer(1) } catch (e) { e["stack"] }`, "[inner(x), outer(1)]"},
+		{`let log = []; let r = try { 1 } finally { let log = push(log, "f"); }; [r, log]`, "[1, []]"},
+		{`let x = 1; try { let x = 2; x } catch { 0 }; x`, "1"},
+		{`let x = 1; try { 1 + true } catch { let x = 2; }; x`, "1"},
+		{`try { let y = 2; y } catch { 0 }; y`, "ERROR: identifier not found: y"},
+		{`let g = fn*() { try { yield 1 } finally { yield 2 } }; map(g(), fn(x) { x })`, "[1, 2]"},
+		{`try { throw "a" } catch (e) { throw "b" }`, "ERROR: b"},
+		{`try { throw "a" } finally { 1 }`, "ERROR: a"},
+		{`try { 1 } finally { throw "f" }`, "ERROR: f"},
+		{`let f = fn() { try { return 1; } finally { 2 }; 3 }; f()`, "1"},
+		{`let f = fn() { try { 1 } finally { return 2; }; 3 }; f()`, "2"},
+		{`throw "top"`, "ERROR: top"},
+		{`let e = 7; try { throw "z" } catch (e) { 1 }; e`, "7"},
+		{`let g = fn*() { try { throw "z" } catch (e) { yield e["message"] } }; map(g(), fn(x) { x })`, "[z]"},
+		{`
+let parse = fn(row) { if (len(row) == 0) { throw "empty row" }; row };
+let safe = fn(row) { try { parse(row) } catch (e) { "skipped" } };
+map(["a", "", "c"], safe)`, "[a, skipped, c]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHashBuiltinFunction(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`keys({"b": 1, "a": 2, "c": 3})`, "[a, b, c]"},
+		{`keys({2: 1, 10: 2, true: 3, "a": 4})`, "[true, 2, 10, a]"},
+		{`values({"b": 1, "a": 2, "c": 3})`, "[2, 1, 3]"},
+		{`items({"b": 1, "a": 2})`, "[[a, 2], [b, 1]]"},
+		{`keys([])`, "ERROR: argument to `keys` must be HASH, got ARRAY"},
+		{`has({"a": 1}, "a")`, "true"},
+		{`has({"a": 1}, "b")`, "false"},
+		{`has({"a": 1}, [])`, "ERROR: unusable as hash key: ARRAY"},
+		{`let h = {"a": 1, "b": 2}; delete(h, "a")`, "{b: 2}"},
+		{`let h = {"a": 1, "b": 2}; delete(h, "a"); h`, "{a: 1, b: 2}"},
+		{`merge({"a": 1, "b": 2}, {"b": 3}, {"c": 4})`, "{a: 1, b: 3, c: 4}"},
+		{`merge({"a": 1}, 1)`, "ERROR: arguments to `merge` must be HASH, got INTEGER"},
+		{`len({"a": 1, "b": 2})`, "2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
 func testEval(input string) object.Object {
 	l := lexer.New(input)
 	p := parser.New(l)
@@ -209,6 +594,317 @@ func testEval(input string) object.Object {
 	return Eval(program, env)
 }
 
+func TestModules(t *testing.T) {
+	files := fstest.MapFS{
+		"lib/strings.monkey": {Data: []byte(`
+import "util.monkey" as u;
+let secret = "hidden";
+export let shout = fn(s) { u.exclaim(upper(s)) };
+export let [first, second] = ["a", "b"];
+export struct Pair { left, right }
+`)},
+		"lib/util.monkey": {Data: []byte(`
+export let exclaim = fn(s) { s + "!" };
+export let loads = push([], 1);
+`)},
+		"cycle/a.monkey":  {Data: []byte(`import "b.monkey" as b; export let a = 1;`)},
+		"cycle/b.monkey":  {Data: []byte(`import "a.monkey" as a; export let b = 2;`)},
+		"broken.monkey":   {Data: []byte(`export let x = ;`)},
+		"failing.monkey":  {Data: []byte(`export let x = 1 + true;`)},
+		"nested.monkey":   {Data: []byte(`let f = fn() { export let x = 1; };`)},
+		"app/main.monkey": {Data: []byte(`import "../lib/strings.monkey" as s; s.shout("hi")`)},
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "lib/strings.monkey" as s; s.shout("hi")`, "HI!"},
+		{`import "lib/strings.monkey" as s; [s.first, s.second]`, "[a, b]"},
+		{`import "lib/strings.monkey" as s; s.Pair(1, 2)`, "Pair{left: 1, right: 2}"},
+		{`import "lib/strings.monkey" as s; s`, "module lib/strings.monkey { Pair, first, second, shout }"},
+		{`import "lib/strings.monkey" as s; s.secret`, "ERROR: module lib/strings.monkey does not export secret"},
+		{`import "lib/strings.monkey" as s; s?.shout("hi")`, "HI!"},
+		{`import "lib/strings.monkey" as s; s?.secret`, "ERROR: module lib/strings.monkey does not export secret"},
+		{`import "lib/strings.monkey" as s; u`, "ERROR: identifier not found: u"},
+		{`import "lib/util.monkey" as a; import "lib/util.monkey" as b; a == b`, "true"},
+		{`import "lib/util.monkey" as a; import "lib/strings.monkey" as s; push(a.loads, 2)`, "[1, 2]"},
+		{`import "cycle/a.monkey" as a; a.a`, "ERROR: import cycle: cycle/a.monkey -> cycle/b.monkey -> cycle/a.monkey"},
+		{`import "missing.monkey" as m; 1`, "ERROR: cannot import missing.monkey: open missing.monkey: file does not exist"},
+		{`import "broken.monkey" as m; 1`, "ERROR: cannot import broken.monkey: no prefix parse function for ; found"},
+		{`import "failing.monkey" as m; 1`, "ERROR: type mismatch: INTEGER + BOOLEAN"},
+		{`import "nested.monkey" as m; 1`, "ERROR: cannot import nested.monkey: export is only allowed at the top level"},
+		{`import "../outside.monkey" as m; 1`, "ERROR: invalid import path: ../outside.monkey"},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		env.SetModuleLoader(object.NewModuleLoader(files))
+
+		program := parser.New(lexer.New(tt.input)).ParseProgram()
+		evaluated := Eval(program, env)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+
+	env := object.NewEnvironment()
+	env.SetModuleLoader(object.NewModuleLoader(files))
+	env.SetFile("app/main.monkey")
+	program := parser.New(lexer.New(`import "../lib/strings.monkey" as s; s.shout("rel")`)).ParseProgram()
+	if evaluated := Eval(program, env); evaluated.Inspect() != "REL!" {
+		t.Errorf("relative import from app/main.monkey failed. got=%q", evaluated.Inspect())
+	}
+
+	evaluated := testEval(`import "lib/strings.monkey" as s; 1`)
+	if evaluated.Inspect() != "ERROR: cannot import lib/strings.monkey: no module loader configured" {
+		t.Errorf("import without loader should fail. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestModuleEnvironment(t *testing.T) {
+	files := fstest.MapFS{
+		"lib.monkey": {Data: []byte(`export let twice = fn(x) { double(x) }; export let at = fn(xs, i) { xs[i] };`)},
+	}
+
+	loader := object.NewModuleLoader(files)
+	loader.NewEnvironment = func() *object.Environment {
+		env := object.NewEnvironment()
+		env.SetStrictIndexing(true)
+		Eval(testParseProgram(`let double = fn(x) { x * 2 };`), env)
+		return env
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "lib.monkey" as l; l.twice(4)`, "8"},
+		{`import "lib.monkey" as l; l.at([1], 5)`, "ERROR: index out of range: index=5, length=1"},
+	}
+
+	for _, tt := range tests {
+		env := object.NewEnvironment()
+		env.SetModuleLoader(loader)
+		evaluated := Eval(testParseProgram(tt.input), env)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestQuoteUnquote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(5)`, `5`},
+		{`quote(5 + 8)`, `(5 + 8)`},
+		{`quote(foobar)`, `foobar`},
+		{`quote(foobar + barfoo)`, `(foobar + barfoo)`},
+		{`quote(unquote(4))`, `4`},
+		{`quote(unquote(4 + 4))`, `8`},
+		{`quote(8 + unquote(4 + 4))`, `(8 + 8)`},
+		{`quote(unquote(4 + 4) + 8)`, `(8 + 8)`},
+		{`let foobar = 8; quote(foobar)`, `foobar`},
+		{`let foobar = 8; quote(unquote(foobar))`, `8`},
+		{`quote(unquote(true))`, `true`},
+		{`quote(unquote(true == false))`, `false`},
+		{`quote(unquote(quote(4 + 4)))`, `(4 + 4)`},
+		{`let quotedInfixExpression = quote(4 + 4); quote(unquote(4 + 4) + unquote(quotedInfixExpression))`, `(8 + (4 + 4))`},
+		{`quote(unquote("a" + "b"))`, `ab`},
+		// Each call substitutes into a fresh copy of the quoted code.
+		{`let f = fn(x) { quote(unquote(x) + 1) }; f(1); f(2)`, `(2 + 1)`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		quote, ok := evaluated.(*object.Quote)
+		if !ok {
+			t.Fatalf("expected *object.Quote. got=%T (%+v)", evaluated, evaluated)
+		}
+
+		if quote.Node == nil {
+			t.Fatalf("quote.Node is nil")
+		}
+
+		if quote.Node.String() != tt.expected {
+			t.Errorf("not equal. got=%q, want=%q", quote.Node.String(), tt.expected)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(1, 2)`, "ERROR: wrong number of arguments. got=2, want=1"},
+		{`quote(unquote(1 + true))`, "ERROR: type mismatch: INTEGER + BOOLEAN"},
+		{`quote(unquote([1]))`, "ERROR: cannot unquote ARRAY"},
+	}
+
+	for _, tt := range errors {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestDefineMacros(t *testing.T) {
+	input := `
+let number = 1;
+let function = fn(x, y) { x + y };
+let mymacro = macro(x, y) { x + y; };
+`
+
+	env := object.NewEnvironment()
+	program := testParseProgram(input)
+
+	DefineMacros(program, env)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("Wrong number of statements. got=%d", len(program.Statements))
+	}
+
+	_, ok := env.Get("number")
+	if ok {
+		t.Fatalf("number should not be defined")
+	}
+	_, ok = env.Get("function")
+	if ok {
+		t.Fatalf("function should not be defined")
+	}
+
+	obj, ok := env.Get("mymacro")
+	if !ok {
+		t.Fatalf("macro not in environment.")
+	}
+
+	macro, ok := obj.(*object.Macro)
+	if !ok {
+		t.Fatalf("object is not Macro. got=%T (%+v)", obj, obj)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("Wrong number of macro parameters. got=%d", len(macro.Parameters))
+	}
+
+	if macro.Parameters[0].String() != "x" || macro.Parameters[1].String() != "y" {
+		t.Fatalf("parameters wrong. got=%v", macro.Parameters)
+	}
+
+	expectedBody := "(x + y)"
+
+	if macro.Body.String() != expectedBody {
+		t.Fatalf("body is not %q. got=%q", expectedBody, macro.Body.String())
+	}
+}
+
+func TestExpandMacros(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`
+let infixExpression = macro() { quote(1 + 2); };
+
+infixExpression();
+`,
+			`(1 + 2)`,
+		},
+		{
+			`
+let reverse = macro(a, b) { quote(unquote(b) - unquote(a)); };
+
+reverse(2 + 2, 10 - 5);
+`,
+			`(10 - 5) - (2 + 2)`,
+		},
+		{
+			`
+let unless = macro(condition, consequence, alternative) {
+	quote(if (!(unquote(condition))) {
+		unquote(consequence);
+	} else {
+		unquote(alternative);
+	});
+};
+
+unless(10 > 5, puts("not greater"), puts("greater"));
+`,
+			`if (!(10 > 5)) { puts("not greater") } else { puts("greater") }`,
+		},
+	}
+
+	for _, tt := range tests {
+		expected := testParseProgram(tt.expected)
+		program := testParseProgram(tt.input)
+
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		expanded, err := ExpandMacros(program, env)
+		if err != nil {
+			t.Fatalf("ExpandMacros failed: %s", err.Inspect())
+		}
+
+		if expanded.String() != expected.String() {
+			t.Errorf("not equal. want=%q, got=%q", expected.String(), expanded.String())
+		}
+	}
+}
+
+func TestMacroDSLs(t *testing.T) {
+	macros := `
+let unless = macro(condition, consequence, alternative) {
+	quote(if (!(unquote(condition))) { unquote(consequence) } else { unquote(alternative) });
+};
+let assert = macro(condition, message) {
+	quote(if (!(unquote(condition))) { throw "assertion failed: " + unquote(message) });
+};
+`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{macros + `unless(1 > 2, "smaller", "bigger")`, "smaller"},
+		{macros + `[unless(1 > 5, "a", "b"), unless(1 < 5, "c", "d")]`, "[a, d]"},
+		{macros + `unless(2 > 1, "smaller", 1 + true)`, "ERROR: type mismatch: INTEGER + BOOLEAN"},
+		{macros + `let f = fn(x) { unless(x == 0, 10 / x, 0) }; [f(2), f(0)]`, "[5, 0]"},
+		{macros + `assert(1 < 2, "math is broken"); "ok"`, "ok"},
+		{macros + `let x = 3; assert(x < 2, "too big")`, "ERROR: assertion failed: too big"},
+		{`let m = macro(x) { 1 }; m(2)`, "ERROR: macro m must return QUOTE, got INTEGER"},
+		{`let m = macro(x) { quote(x) }; m()`, "ERROR: wrong number of arguments to macro m. got=0, want=1"},
+		{`let m = macro(x) { x + true }; m(1)`, "ERROR: type mismatch: QUOTE + BOOLEAN"},
+		{`macro(x) { x }`, "ERROR: macros must be defined with a top-level let"},
+	}
+
+	for _, tt := range tests {
+		program := testParseProgram(tt.input)
+		macroEnv := object.NewEnvironment()
+		DefineMacros(program, macroEnv)
+
+		var evaluated object.Object
+		expanded, err := ExpandMacros(program, macroEnv)
+		if err != nil {
+			evaluated = err
+		} else {
+			evaluated = Eval(expanded, object.NewEnvironment())
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func testParseProgram(input string) *ast.Program {
+	l := lexer.New(input)
+	p := parser.New(l)
+	return p.ParseProgram()
+}
+
 func TestStringConcatenation(t *testing.T) {
 	input := `"Hello" + " " + "World!"`
 
@@ -223,6 +919,20 @@ func TestStringConcatenation(t *testing.T) {
 	}
 }
 
+func TestStringRepetition(t *testing.T) {
+	input := `"-" * 3 + "ab" * 2`
+
+	evaluated := testEval(input)
+	str, ok := evaluated.(*object.String)
+	if !ok {
+		t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if str.Value != "---abab" {
+		t.Errorf("String has wrong value. got=%q", str.Value)
+	}
+}
+
 func TestStringLiteral(t *testing.T) {
 	input := `"Hello World!";`
 	evaluated := testEval(input)
@@ -294,6 +1004,39 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let [a, b] = [1, 2]; a + b`, "3"},
+		{`let [a, b, ...rest] = [1, 2, 3, 4]; [a, b, rest]`, "[1, 2, [3, 4]]"},
+		{`let [a, ...rest] = [1]; rest`, "[]"},
+		{`let [_, b] = [1, 2]; b`, "2"},
+		{`let pair = fn() { [1, "one"] }; let [n, s] = pair(); s`, "one"},
+		{`let [a, [b, c]] = [1, [2, 3]]; a + b + c`, "6"},
+		{`let {name, age: years} = {"name": "ann", "age": 3}; [name, years]`, "[ann, 3]"},
+		{`let {"pos": [x, y]} = {"pos": [4, 5]}; x * y`, "20"},
+		{`let [a, b] = [1]; a`, "ERROR: cannot destructure [1] into [a, b]: expected 2 elements, got 1"},
+		{`let [a, b, ...rest] = [1]; a`, "ERROR: cannot destructure [1] into [a, b, ...rest]: expected at least 2 elements, got 1"},
+		{`let [a] = 1; a`, "ERROR: cannot destructure 1 into [a]: expected ARRAY, got INTEGER"},
+		{`let {name} = {"age": 3}; name`, "ERROR: cannot destructure {age: 3} into {name: name}: missing key name"},
+		{`let {name} = [1]; name`, "ERROR: cannot destructure [1] into {name: name}: expected HASH, got ARRAY"},
+		{`let add = fn([a, b]) { a + b }; add([1, 2])`, "3"},
+		{`let greet = fn(greeting, {name}) { greeting + " " + name }; greet("hi", {"name": "bo"})`, "hi bo"},
+		{`let head = fn([h, ..._]) { h }; map([[1, 2], [3]], head)`, "[1, 3]"},
+		{`let add = fn([a, b]) { a + b }; add([1])`, "ERROR: cannot destructure [1] into [a, b]: expected 2 elements, got 1"},
+		{`let add = fn([a, b]) { a + b }; add()`, "ERROR: wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
 func TestErrorHandling(t *testing.T) {
 	tests := []struct {
 		input           string
@@ -342,6 +1085,26 @@ if (10 > 1) {
 			`"Hello" - "World"`,
 			"unknown operator: STRING - STRING",
 		},
+		{
+			`"-" - 3`,
+			"type mismatch: STRING - INTEGER",
+		},
+		{
+			`"-" * -1`,
+			"negative repeat count: -1",
+		},
+		{
+			`"ab" * 4611686018427387904`,
+			"repeat count too large: 4611686018427387904",
+		},
+		{
+			`1 in "abc"`,
+			"type mismatch: INTEGER in STRING",
+		},
+		{
+			`1 in 2`,
+			"unknown operator: INTEGER in INTEGER",
+		},
 		{
 			`{"name": "Money"}[fn(x) { x }];`,
 			"unusable as hash key: FUNCTION",
@@ -406,6 +1169,10 @@ func TestIfElseExpression(t *testing.T) {
 		{"if (1 > 2) { 10 }", nil},
 		{"if (1 > 2) { 10 } else { 20 }", 20},
 		{"if (1 < 2) { 10 } else { 20 }", 10},
+		{"if (1 > 2) { 10 } else if (2 > 1) { 20 } else { 30 }", 20},
+		{"if (1 > 2) { 10 } else if (2 > 3) { 20 } else { 30 }", 30},
+		{"if (1 > 2) { 10 } else if (2 > 3) { 20 }", nil},
+		{"if (false) { 1 } else if (false) { 2 } else if (true) { 3 }", 3},
 	}
 
 	for _, tt := range tests {
@@ -419,6 +1186,176 @@ func TestIfElseExpression(t *testing.T) {
 	}
 }
 
+func TestConditionalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`true ? 1 : 2`, "1"},
+		{`1 > 2 ? "yes" : "no"`, "no"},
+		{`let n = 0; n == 0 ? "zero" : n > 0 ? "positive" : "negative"`, "zero"},
+		{`let n = -3; n == 0 ? "zero" : n > 0 ? "positive" : "negative"`, "negative"},
+		{`true ? 1 : 1 + true`, "1"},
+		{`let h = {"a": 1}; h["a"] ?? 0`, "1"},
+		{`let h = {"a": 1}; h["b"] ?? 0`, "0"},
+		{`false ?? 1`, "false"},
+		{`1 ?? 1 + true`, "1"},
+		{`let h = {"a": {"b": 2}}; h?.a?.b`, "2"},
+		{`let h = {"a": {"b": 2}}; h?["a"]?["b"]`, "2"},
+		{`let h = {"a": 1}; h?.b?.c`, "null"},
+		{`let h = {"a": 1}; h?.b?.c ?? "none"`, "none"},
+		{`"use strict"; let h = {"a": 1}; h?.b ?? "none"`, "none"},
+		{`[1, 2]?[1]`, "2"},
+		{`let c = false; c ?[1] : [2]`, "[2]"},
+		{`let h = {"a": 1}; h["b"]["c"]`, "ERROR: index operator not supported: NULL"},
+		{`let h = {"a": {}}; h?.a?.b.c`, "null"},
+		{`let h = {"a": {}}; h?.a?.b.c[0](1)[1:] ?? "none"`, "none"},
+		{`let h = {}["x"]; h?.a[1 + true](len(1, 2))`, "null"},
+		{`let h = {"a": {}["x"]}; h?.a.b`, "null"},
+		{`let h = {"f": fn(x) { x * 2 }}; h?.f(3)`, "6"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestArrowFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let double = x => x * 2; double(4)`, "8"},
+		{`let add = (a, b) => a + b; add(1, 2)`, "3"},
+		{`let one = () => 1; one()`, "1"},
+		{`map([1, 2, 3], x => x * x)`, "[1, 4, 9]"},
+		{`reduce([1, 2, 3], 0, (acc, x) => acc + x)`, "6"},
+		{`let adder = x => y => x + y; adder(2)(3)`, "5"},
+		{`let f = x => { let y = x + 1; y * 2 }; f(1)`, "4"},
+		{`let first = ([a, ..._]) => a; first([7, 8])`, "7"},
+		{`[1, 2, 3] |> filter(x => x > 1) |> map(x => x * 10)`, "[20, 30]"},
+		{`match (3) { n if (n > 2) => "big", _ => "small" }`, "big"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStructs(t *testing.T) {
+	point := `
+struct Point { x, y }
+impl Point {
+	norm() { self.x * self.x + self.y * self.y }
+	scale(k) { Point(self.x * k, self.y * k) }
+	add(other) { Point(self.x + other.x, self.y + other.y) }
+}
+`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{point + `Point(1, 2)`, "Point{x: 1, y: 2}"},
+		{point + `Point`, "struct Point { x, y }"},
+		{point + `let p = Point(3, 4); p.x + p.y`, "7"},
+		{point + `Point(3, 4).norm()`, "25"},
+		{point + `Point(1, 2).scale(3)`, "Point{x: 3, y: 6}"},
+		{point + `Point(1, 2).add(Point(3, 4)).scale(2).x`, "8"},
+		{point + `let norm = Point(1, 1).norm; norm()`, "2"},
+		{point + `map([Point(1, 0), Point(0, 2)], p => p.norm())`, "[1, 4]"},
+		{point + `map([1, 2], x => Point(x, x)) |> map(p => p.y)`, "[1, 2]"},
+		{point + `Point(1, 2) == Point(1, 2)`, "true"},
+		{point + `Point(1, 2) == Point(2, 1)`, "false"},
+		{point + `match (Point(0, 5)) { p: Point if p.x == 0 => p.y, _ => 0 }`, "5"},
+		{point + `struct Other { x, y } match (Other(1, 2)) { _: Point => "point", _: Other => "other" }`, "other"},
+		{point + `Point(1, 2).z`, "ERROR: Point has no field or method z"},
+		{point + `let p = Point(3, 4); p?.x + p?.y`, "7"},
+		{point + `Point(3, 4)?.norm()`, "25"},
+		{point + `let h = {"p": Point(1, 2)}; h?.p?.scale(2)?.y`, "4"},
+		{point + `let h = {}; h?.p?.x`, "null"},
+		{point + `Point(1, 2)?.z`, "ERROR: Point has no field or method z"},
+		{point + `Point(1)`, "ERROR: wrong number of arguments to Point. got=1, want=2"},
+		{point + `Point(1, 2).scale()`, "ERROR: wrong number of arguments. got=0, want=1"},
+		{`1.x`, "ERROR: member access not supported: INTEGER"},
+		{`impl Missing { f() { 1 } }`, "ERROR: identifier not found: Missing"},
+		{`let Missing = 1; impl Missing { f() { 1 } }`, "ERROR: impl target must be STRUCT, got INTEGER"},
+		{point + `try { Point(1, 2).z } catch (e) { e["kind"] }`, "KeyError"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHashMemberAccess(t *testing.T) {
+	config := `let cfg = {"db": {"host": "localhost", "port": 5432}, "name": "app"};`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{config + `cfg.name`, "app"},
+		{config + `cfg.db.host`, "localhost"},
+		{config + `cfg.db.port + 1`, "5433"},
+		{config + `cfg.db.user`, "ERROR: key not found: user in cfg.db"},
+		{config + `let c = {"cfg": cfg}; c?.cfg.db.user`, "ERROR: key not found: user in c?.cfg.db"},
+		{config + `cfg.cache.size`, "ERROR: key not found: cache in cfg"},
+		{config + `cfg?.cache?.size ?? 0`, "0"},
+		{`let h = {"double": x => x * 2}; h.double(21)`, "42"},
+		{`let counter = {"add": fn(a, b) { a + b }}; counter.add(1, 2)`, "3"},
+		{`let h = {"f": 1}; h.f()`, "ERROR: not a function: INTEGER"},
+		{`let h = {1: "one"}; h.one`, "ERROR: key not found: one in h"},
+		{`[1].x`, "ERROR: member access not supported: ARRAY"},
+		{config + `try { cfg.db.user } catch (e) { e["kind"] }`, "KeyError"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestPipelineAndComposition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[1, 2, 3] |> len`, "3"},
+		{`[1, 2, 3] |> map(fn(x) { x * 2 })`, "[2, 4, 6]"},
+		{`[1, 2, 3, 4] |> filter(fn(x) { x > 1 }) |> map(fn(x) { x * 10 }) |> reduce(0, fn(a, b) { a + b })`, "90"},
+		{`let add = fn(a, b) { a + b }; 1 |> add(2)`, "3"},
+		{`"a,b" |> split(",") |> join("-")`, "a-b"},
+		{`let inc = fn(x) { x + 1 }; let double = fn(x) { x * 2 }; let f = inc >> double; f(3)`, "8"},
+		{`let inc = fn(x) { x + 1 }; (inc >> inc >> inc)(0)`, "3"},
+		{`let words = fn(s) { split(s, " ") }; (words >> len)("a b c")`, "3"},
+		{`let add = fn(a, b) { a + b }; let neg = fn(x) { -x }; (add >> neg)(1, 2)`, "-3"},
+		{`let inc = fn(x) { x + 1 }; 1 |> inc >> inc`, "3"},
+		{`let inc = fn(x) { x + 1 }; map([1, 2], inc >> inc)`, "[3, 4]"},
+		{`1 |> 2`, "ERROR: not a function: INTEGER"},
+		{`let inc = fn(x) { x + 1 }; inc >> 1`, "ERROR: operands of >> must be callable, got FUNCTION >> INTEGER"},
+		{`let f = fn(x) { x + true }; (len >> f)("ab")`, "ERROR: type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %s. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
 func testNullObject(t *testing.T, obj object.Object) bool {
 	if obj != NULL {
 		t.Errorf("object is not NULL. got=%T (%+v)", obj, obj)
@@ -497,6 +1434,30 @@ func TestEvalBooleanExpression(t *testing.T) {
 		{"(1 < 2) == false", false},
 		{"(1 > 2) == true", false},
 		{"(1 > 2) == false", true},
+		{`"a" == "a"`, true},
+		{`"a" != "a"`, false},
+		{`"a" == "b"`, false},
+		{"[1, [2, 3]] == [1, [2, 3]]", true},
+		{"[1, 2] == [1, 2, 3]", false},
+		{`{"a": [1]} == {"a": [1]}`, true},
+		{`{"a": 1} != {"a": 2}`, true},
+		{"let f = fn(x) { x }; f == f", true},
+		{"fn(x) { x } == fn(x) { x }", false},
+		{"let a = [1]; is(a, a)", true},
+		{"is([1], [1])", false},
+		{"is(true, true)", true},
+		{"1 <= 1", true},
+		{"2 >= 3", false},
+		{`"apple" < "banana"`, true},
+		{`"apple" > "banana"`, false},
+		{`"abc" <= "abc"`, true},
+		{`"abd" >= "abc"`, true},
+		{`"ell" in "hello"`, true},
+		{`"xyz" in "hello"`, false},
+		{`[2] in [1, [2], 3]`, true},
+		{`4 in [1, 2, 3]`, false},
+		{`"a" in {"a": 1}`, true},
+		{`"b" in {"a": 1}`, false},
 	}
 
 	for _, tt := range tests {
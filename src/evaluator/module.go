@@ -0,0 +1,110 @@
+package evaluator
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
+)
+
+func evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object {
+	loader := env.ModuleLoader()
+	if loader == nil {
+		return newError("cannot import %s: no module loader configured", node.Path.Value)
+	}
+
+	file, ok := resolveImportPath(env.File(), node.Path.Value)
+	if !ok {
+		return newTypedError(object.VALUE_ERROR, "invalid import path: %s", node.Path.Value)
+	}
+
+	module := importModule(loader, file)
+	if err, ok := module.(*object.Error); ok {
+		err.Stack = append(err.Stack, node.String())
+		return err
+	}
+
+	env.Set(node.Alias.Value, module)
+	return nil
+}
+
+// resolveImportPath resolves target relative to the directory of the
+// importing file. The result must stay inside the loader's filesystem.
+func resolveImportPath(importer, target string) (string, bool) {
+	resolved := path.Join(path.Dir(importer), target)
+	return resolved, fs.ValidPath(resolved)
+}
+
+// importModule evaluates the file at p in a fresh environment made by the
+// loader and returns its exports, reusing the cached module when p was
+// imported before.
+func importModule(loader *object.ModuleLoader, p string) object.Object {
+	if module, ok := loader.Lookup(p); ok {
+		return module
+	}
+
+	if cycle, ok := loader.Begin(p); !ok {
+		return newError("import cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	var module *object.Module
+	defer func() { loader.End(p, module) }()
+
+	source, err := fs.ReadFile(loader.FS, p)
+	if err != nil {
+		return newError("cannot import %s: %s", p, err)
+	}
+
+	par := parser.New(lexer.New(string(source)))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		return newError("cannot import %s: %s", p, strings.Join(par.Errors(), "; "))
+	}
+
+	macroEnv := object.NewEnvironment()
+	DefineMacros(program, macroEnv)
+	expanded, macroErr := ExpandMacros(program, macroEnv)
+	if macroErr != nil {
+		return macroErr
+	}
+
+	env := loader.NewEnvironment()
+	env.SetModuleLoader(loader)
+	env.SetFile(p)
+
+	result := Eval(expanded, env)
+	if isError(result) {
+		return result
+	}
+
+	module = &object.Module{Path: p, Exports: make(map[string]object.Object)}
+	for _, stmt := range program.Statements {
+		export, ok := stmt.(*ast.ExportStatement)
+		if !ok {
+			continue
+		}
+		for _, name := range declaredNames(export.Statement) {
+			module.Exports[name], _ = env.Get(name)
+		}
+	}
+
+	return module
+}
+
+// declaredNames lists the names a let or struct statement binds.
+func declaredNames(stmt ast.Statement) []string {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		if stmt.Pattern != nil {
+			return patternNames(stmt.Pattern)
+		}
+		return []string{stmt.Name.Value}
+	case *ast.StructStatement:
+		return []string{stmt.Name.Value}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+)
+
+var patternTypes = map[string]bool{
+	object.INTEGER_OBJ:     true,
+	object.BOOLEAN_OBJ:     true,
+	object.NULL_OBJ:        true,
+	object.FUNCTION_OBJ:    true,
+	object.STRING_OBJ:      true,
+	object.BUILTIN_OBJ:     true,
+	object.ARRAY_OBJ:       true,
+	object.HASH_OBJ:        true,
+	object.RANGE_OBJ:       true,
+	object.GENERATOR_OBJ:   true,
+	object.COMPOSITION_OBJ: true,
+	object.STRUCT_OBJ:      true,
+	object.INSTANCE_OBJ:    true,
+}
+
+func evalMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	value := Eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	for _, arm := range node.Arms {
+		bindings := make(map[string]object.Object)
+		mismatch, err := matchPattern(arm.Pattern, value, bindings, env)
+		if err != nil {
+			return err
+		}
+		if mismatch != "" {
+			continue
+		}
+
+		// The names an arm binds are local to its guard and body, so they
+		// never overwrite variables of the enclosing scope.
+		armEnv := object.NewBlockEnvironment(env)
+		for name, val := range bindings {
+			armEnv.Set(name, val)
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		result := Eval(arm.Body, armEnv)
+		if result == nil {
+			return NULL
+		}
+		return result
+	}
+
+	return newTypedError(object.MATCH_ERROR, "non-exhaustive match: no arm matches %s", value.Inspect())
+}
+
+// destructure binds the names in pattern to the matching parts of value in
+// target, evaluating pattern keys in env. A value that does not fit the
+// pattern is a MatchError.
+func destructure(pattern ast.Pattern, value object.Object, env, target *object.Environment) *object.Error {
+	bindings := make(map[string]object.Object)
+	mismatch, err := matchPattern(pattern, value, bindings, env)
+	if err != nil {
+		return err
+	}
+	if mismatch != "" {
+		return newTypedError(object.MATCH_ERROR, "cannot destructure %s into %s: %s", value.Inspect(), pattern.String(), mismatch)
+	}
+
+	for name, val := range bindings {
+		target.Set(name, val)
+	}
+	return nil
+}
+
+// matchPattern checks value against pattern, collecting the names it binds.
+// It returns a description of the first mismatch, or "" when value matches.
+func matchPattern(pattern ast.Pattern, value object.Object, bindings map[string]object.Object, env *object.Environment) (string, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return "", nil
+
+	case *ast.BindingPattern:
+		bindings[pattern.Name.Value] = value
+		return "", nil
+
+	case *ast.LiteralPattern:
+		expected := Eval(pattern.Value, env)
+		if err, ok := expected.(*object.Error); ok {
+			return "", err
+		}
+		if !object.Equal(expected, value) {
+			return fmt.Sprintf("expected %s, got %s", expected.Inspect(), value.Inspect()), nil
+		}
+		return "", nil
+
+	case *ast.TypePattern:
+		mismatch, err := matchType(pattern.TypeName, value, env)
+		if err != nil || mismatch != "" {
+			return mismatch, err
+		}
+		if pattern.Name != nil {
+			bindings[pattern.Name.Value] = value
+		}
+		return "", nil
+
+	case *ast.ArrayPattern:
+		return matchArrayPattern(pattern, value, bindings, env)
+
+	case *ast.HashPattern:
+		return matchHashPattern(pattern, value, bindings, env)
+	}
+
+	return "", newError("unknown pattern: %s", pattern.String())
+}
+
+// matchType checks value against a type pattern. The type is either one
+// of the object types or the name of a struct in scope.
+func matchType(typeName string, value object.Object, env *object.Environment) (string, *object.Error) {
+	if patternTypes[typeName] {
+		if string(value.Type()) != typeName {
+			return fmt.Sprintf("expected %s, got %s", typeName, value.Type()), nil
+		}
+		return "", nil
+	}
+
+	obj, ok := env.Get(typeName)
+	structType, isStruct := obj.(*object.Struct)
+	if !ok || !isStruct {
+		return "", newTypedError(object.TYPE_ERROR, "unknown type in pattern: %s", typeName)
+	}
+
+	if instance, ok := value.(*object.Instance); !ok || instance.Struct != structType {
+		return fmt.Sprintf("expected %s, got %s", typeName, typeNameOf(value)), nil
+	}
+	return "", nil
+}
+
+func matchArrayPattern(pattern *ast.ArrayPattern, value object.Object, bindings map[string]object.Object, env *object.Environment) (string, *object.Error) {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return fmt.Sprintf("expected ARRAY, got %s", value.Type()), nil
+	}
+
+	length := len(array.Elements)
+	want := len(pattern.Elements)
+	if pattern.Rest == nil && length != want {
+		return fmt.Sprintf("expected %d elements, got %d", want, length), nil
+	}
+	if pattern.Rest != nil && length < want {
+		return fmt.Sprintf("expected at least %d elements, got %d", want, length), nil
+	}
+
+	for i, element := range pattern.Elements {
+		mismatch, err := matchPattern(element, array.Elements[i], bindings, env)
+		if err != nil || mismatch != "" {
+			return mismatch, err
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := make([]object.Object, length-want)
+		copy(rest, array.Elements[want:])
+		bindings[pattern.Rest.Value] = &object.Array{Elements: rest}
+	}
+
+	return "", nil
+}
+
+func matchHashPattern(pattern *ast.HashPattern, value object.Object, bindings map[string]object.Object, env *object.Environment) (string, *object.Error) {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return fmt.Sprintf("expected HASH, got %s", value.Type()), nil
+	}
+
+	for i, keyNode := range pattern.Keys {
+		key := Eval(keyNode, env)
+		if err, ok := key.(*object.Error); ok {
+			return "", err
+		}
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return "", newTypedError(object.TYPE_ERROR, "unusable as hash key: %s", key.Type())
+		}
+
+		pair, ok := hash.Pairs[hashKey.HashKey()]
+		if !ok {
+			return fmt.Sprintf("missing key %s", key.Inspect()), nil
+		}
+
+		mismatch, err := matchPattern(pattern.Values[i], pair.Value, bindings, env)
+		if err != nil || mismatch != "" {
+			return mismatch, err
+		}
+	}
+
+	return "", nil
+}
+
+// patternNames lists the names pattern binds, in source order.
+func patternNames(pattern ast.Pattern) []string {
+	switch pattern := pattern.(type) {
+	case *ast.BindingPattern:
+		return []string{pattern.Name.Value}
+	case *ast.TypePattern:
+		if pattern.Name != nil {
+			return []string{pattern.Name.Value}
+		}
+	case *ast.ArrayPattern:
+		names := []string{}
+		for _, el := range pattern.Elements {
+			names = append(names, patternNames(el)...)
+		}
+		if pattern.Rest != nil {
+			names = append(names, pattern.Rest.Value)
+		}
+		return names
+	case *ast.HashPattern:
+		names := []string{}
+		for _, value := range pattern.Values {
+			names = append(names, patternNames(value)...)
+		}
+		return names
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+func isQuoteCall(node *ast.CallExpression) bool {
+	ident, ok := node.Function.(*ast.Identifier)
+	return ok && ident.Value == "quote"
+}
+
+func quote(node *ast.CallExpression, env *object.Environment) object.Object {
+	if len(node.Arguments) != 1 {
+		return newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments. got=%d, want=1", len(node.Arguments))
+	}
+
+	var err *object.Error
+	quoted := evalUnquoteCalls(node.Arguments[0], env, &err)
+	if err != nil {
+		return err
+	}
+
+	return &object.Quote{Node: quoted}
+}
+
+// evalUnquoteCalls replaces each unquote(x) inside quoted with the AST of
+// x's value. The first failure is stored in err and stops further
+// evaluation. quoted itself is left untouched, since it is part of a
+// function or macro body that may be evaluated again.
+func evalUnquoteCalls(quoted ast.Node, env *object.Environment, err **object.Error) ast.Node {
+	return ast.Modify(ast.Copy(quoted), func(node ast.Node) ast.Node {
+		if *err != nil || !isUnquoteCall(node) {
+			return node
+		}
+
+		call := node.(*ast.CallExpression)
+		if len(call.Arguments) != 1 {
+			*err = newTypedError(object.ARGUMENT_ERROR, "wrong number of arguments to `unquote`. got=%d, want=1", len(call.Arguments))
+			return node
+		}
+
+		unquoted := Eval(call.Arguments[0], env)
+		if e, ok := unquoted.(*object.Error); ok {
+			*err = e
+			return node
+		}
+
+		converted := convertObjectToASTNode(unquoted)
+		if converted == nil {
+			*err = newTypedError(object.TYPE_ERROR, "cannot unquote %s", unquoted.Type())
+			return node
+		}
+		return converted
+	})
+}
+
+func isUnquoteCall(node ast.Node) bool {
+	callExpression, ok := node.(*ast.CallExpression)
+	if !ok {
+		return false
+	}
+
+	ident, ok := callExpression.Function.(*ast.Identifier)
+	return ok && ident.Value == "unquote"
+}
+
+func convertObjectToASTNode(obj object.Object) ast.Node {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		t := token.Token{
+			Type:    token.INT,
+			Literal: fmt.Sprintf("%d", obj.Value),
+		}
+		return &ast.IntegerLiteral{Token: t, Value: obj.Value}
+
+	case *object.Boolean:
+		var t token.Token
+		if obj.Value {
+			t = token.Token{Type: token.TRUE, Literal: "true"}
+		} else {
+			t = token.Token{Type: token.FALSE, Literal: "false"}
+		}
+		return &ast.Boolean{Token: t, Value: obj.Value}
+
+	case *object.String:
+		t := token.Token{Type: token.STRING, Literal: obj.Value}
+		return &ast.StringLiteral{Token: t, Value: obj.Value}
+
+	case *object.Quote:
+		return obj.Node
+
+	default:
+		return nil
+	}
+}
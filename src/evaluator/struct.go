@@ -0,0 +1,42 @@
+package evaluator
+
+import (
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+)
+
+func evalStructStatement(node *ast.StructStatement) *object.Struct {
+	fields := make([]string, len(node.Fields))
+	for i, field := range node.Fields {
+		fields[i] = field.Value
+	}
+
+	return &object.Struct{Name: node.Name.Value, Fields: fields, Methods: make(map[string]*object.Function)}
+}
+
+func evalImplStatement(node *ast.ImplStatement, env *object.Environment) *object.Error {
+	obj, ok := env.Get(node.Name.Value)
+	if !ok {
+		return newTypedError(object.NAME_ERROR, "identifier not found: %s", node.Name.Value)
+	}
+	structType, ok := obj.(*object.Struct)
+	if !ok {
+		return newTypedError(object.TYPE_ERROR, "impl target must be STRUCT, got %s", obj.Type())
+	}
+
+	for _, method := range node.Methods {
+		lit := method.Function
+		structType.Methods[method.Name.Value] = &object.Function{Parameters: lit.Parameters, Patterns: lit.Patterns, Body: lit.Body, Env: env}
+	}
+
+	return nil
+}
+
+// typeNameOf names the type of obj for messages, using the struct name for
+// instances.
+func typeNameOf(obj object.Object) string {
+	if instance, ok := obj.(*object.Instance); ok {
+		return instance.Struct.Name
+	}
+	return string(obj.Type())
+}
@@ -1,13 +1,15 @@
 package lexer
 
 import (
+	"strings"
+
 	"github.com/g-hyoga/writing-interpreter-in-go/src/logger"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
 	"github.com/sirupsen/logrus"
 )
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.logger = logger.New()
 	l.logger.Debug("[lexer] New")
 	l.readChar()
@@ -19,10 +21,23 @@ type Lexer struct {
 	position     int  // current position in input (points to current char)
 	readPosition int  // current reading position in input (after current char)
 	ch           byte // current char under examination
+	line         int  // line of the current char
+	column       int  // column of the current char
+	comments     []token.Token
 	logger       *logrus.Logger
 }
 
 func (l *Lexer) readChar() {
+	if l.readPosition > len(l.input) {
+		// Already at the end; keep the position of EOF.
+		return
+	}
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	l.column++
+
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -32,15 +47,19 @@ func (l *Lexer) readChar() {
 	l.readPosition += 1
 }
 
-func (l *Lexer) readString() string {
+// readString reads up to the closing quote and reports whether there was
+// one before the end of the input.
+func (l *Lexer) readString() (string, bool) {
 	position := l.position + 1
 	for {
 		l.readChar()
 		if l.ch == '"' {
-			break
+			return l.input[position:l.position], true
+		}
+		if l.ch == 0 {
+			return l.input[position:l.position], false
 		}
 	}
-	return l.input[position:l.position]
 }
 
 func (l *Lexer) readIdentifier() string {
@@ -52,9 +71,38 @@ func (l *Lexer) readIdentifier() string {
 }
 
 func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+	for {
+		switch {
+		case l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r':
+			l.readChar()
+		case l.ch == '/' && l.peekChar() == '/':
+			l.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// skipComment skips a // comment up to the end of the line, keeping it
+// for Comments.
+func (l *Lexer) skipComment() {
+	pos := l.pos()
+	position := l.position
+	for l.ch != '\n' && l.ch != 0 {
 		l.readChar()
 	}
+
+	text := strings.TrimRight(l.input[position:l.position], " \t\r")
+	l.comments = append(l.comments, token.Token{Type: token.COMMENT, Literal: text, Pos: pos})
+}
+
+// Comments returns the comments skipped so far, in source order.
+func (l *Lexer) Comments() []token.Token {
+	return l.comments
+}
+
+func (l *Lexer) pos() token.Position {
+	return token.Position{Line: l.line, Column: l.column}
 }
 
 func (l *Lexer) readNumber() string {
@@ -83,7 +131,10 @@ func (l *Lexer) NextToken() token.Token {
 		}).Debug("[lexer] NextToken")
 	}()
 
+	start := l.position
 	l.skipWhitespace()
+	spaced := l.position != start
+	pos := l.pos()
 
 	switch l.ch {
 	case '=':
@@ -91,6 +142,10 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.EQ, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: string(ch) + string(l.ch)}
 		} else {
 			tok = newToken(token.ASSIGN, l.ch)
 		}
@@ -111,15 +166,70 @@ func (l *Lexer) NextToken() token.Token {
 	case '*':
 		tok = newToken(token.ASTERISK, l.ch)
 	case '<':
-		tok = newToken(token.LT, l.ch)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.LT_EQ, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.LT, l.ch)
+		}
 	case '>':
-		tok = newToken(token.GT, l.ch)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.GT_EQ, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.COMPOSE, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.GT, l.ch)
+		}
+	case '.':
+		if l.peekChar() == '.' {
+			l.readChar()
+			if l.peekChar() == '<' {
+				l.readChar()
+				tok = token.Token{Type: token.DOTDOT_LT, Literal: "..<"}
+			} else if l.peekChar() == '.' {
+				l.readChar()
+				tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+			} else {
+				tok = token.Token{Type: token.DOTDOT, Literal: ".."}
+			}
+		} else {
+			tok = newToken(token.DOT, l.ch)
+		}
 	case ',':
 		tok = newToken(token.COMMA, l.ch)
 	case ';':
 		tok = newToken(token.SEMICOLON, l.ch)
 	case ':':
 		tok = newToken(token.COLON, l.ch)
+	case '|':
+		if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.PIPE, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.ILLEGAL, l.ch)
+		}
+	case '?':
+		switch {
+		case l.peekChar() == '?':
+			l.readChar()
+			tok = token.Token{Type: token.NULLISH, Literal: "??"}
+		case l.peekChar() == '.':
+			l.readChar()
+			tok = token.Token{Type: token.OPTIONAL_DOT, Literal: "?."}
+		case l.peekChar() == '[' && !spaced:
+			// Optional indexing is written attached to its operand, as in
+			// xs?[0]; c ?[1] : [2] is a conditional.
+			l.readChar()
+			tok = token.Token{Type: token.OPTIONAL_INDEX, Literal: "?["}
+		default:
+			tok = newToken(token.QUESTION, l.ch)
+		}
 	case '(':
 		tok = newToken(token.LPAREN, l.ch)
 	case ')':
@@ -133,8 +243,12 @@ func (l *Lexer) NextToken() token.Token {
 	case ']':
 		tok = newToken(token.RBRACKET, l.ch)
 	case '"':
-		tok.Type = token.STRING
-		tok.Literal = l.readString()
+		str, terminated := l.readString()
+		if terminated {
+			tok = token.Token{Type: token.STRING, Literal: str}
+		} else {
+			tok = token.Token{Type: token.ILLEGAL, Literal: `"` + str}
+		}
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
@@ -142,16 +256,19 @@ func (l *Lexer) NextToken() token.Token {
 		if isLetter(l.ch) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
+			tok.Pos = pos
 			return tok
 		} else if isDigit(l.ch) {
 			tok.Type = token.INT
 			tok.Literal = l.readNumber()
+			tok.Pos = pos
 			return tok
 		} else {
 			tok = newToken(token.ILLEGAL, l.ch)
 		}
 	}
 
+	tok.Pos = pos
 	l.readChar()
 	return tok
 }
@@ -31,6 +31,16 @@ if (5 < 10) {
 "foo bar"
 [1, 2];
 {"foo": "bar"}
+1 <= 2 >= 1
+"a" in "abc"
+0..10 step 2
+0..<n
+match (x) { [a, ...rest] => a }
+a ?? b ? c : d?.e?["f"]
+xs |> f >> g
+struct P { x } impl P { p.x }
+// a comment up to the end of the line
+10 / 2 // trailing comment
 `
 
 	tests := []struct {
@@ -121,6 +131,68 @@ if (5 < 10) {
 		{token.COLON, ":"},
 		{token.STRING, "bar"},
 		{token.RBRACE, "}"},
+		{token.INT, "1"},
+		{token.LT_EQ, "<="},
+		{token.INT, "2"},
+		{token.GT_EQ, ">="},
+		{token.INT, "1"},
+		{token.STRING, "a"},
+		{token.IN, "in"},
+		{token.STRING, "abc"},
+		{token.INT, "0"},
+		{token.DOTDOT, ".."},
+		{token.INT, "10"},
+		{token.IDENT, "step"},
+		{token.INT, "2"},
+		{token.INT, "0"},
+		{token.DOTDOT_LT, "..<"},
+		{token.IDENT, "n"},
+		{token.MATCH, "match"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "rest"},
+		{token.RBRACKET, "]"},
+		{token.ARROW, "=>"},
+		{token.IDENT, "a"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "a"},
+		{token.NULLISH, "??"},
+		{token.IDENT, "b"},
+		{token.QUESTION, "?"},
+		{token.IDENT, "c"},
+		{token.COLON, ":"},
+		{token.IDENT, "d"},
+		{token.OPTIONAL_DOT, "?."},
+		{token.IDENT, "e"},
+		{token.OPTIONAL_INDEX, "?["},
+		{token.STRING, "f"},
+		{token.RBRACKET, "]"},
+		{token.IDENT, "xs"},
+		{token.PIPE, "|>"},
+		{token.IDENT, "f"},
+		{token.COMPOSE, ">>"},
+		{token.IDENT, "g"},
+		{token.STRUCT, "struct"},
+		{token.IDENT, "P"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "x"},
+		{token.RBRACE, "}"},
+		{token.IMPL, "impl"},
+		{token.IDENT, "P"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "p"},
+		{token.DOT, "."},
+		{token.IDENT, "x"},
+		{token.RBRACE, "}"},
+		{token.INT, "10"},
+		{token.SLASH, "/"},
+		{token.INT, "2"},
 		{token.EOF, ""},
 	}
 
@@ -140,3 +212,91 @@ if (5 < 10) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := "let x = 5;\n\tx >= 10\n"
+
+	tests := []struct {
+		expectedType token.TokenType
+		expectedPos  token.Position
+	}{
+		{token.LET, token.Position{Line: 1, Column: 1}},
+		{token.IDENT, token.Position{Line: 1, Column: 5}},
+		{token.ASSIGN, token.Position{Line: 1, Column: 7}},
+		{token.INT, token.Position{Line: 1, Column: 9}},
+		{token.SEMICOLON, token.Position{Line: 1, Column: 10}},
+		{token.IDENT, token.Position{Line: 2, Column: 2}},
+		{token.GT_EQ, token.Position{Line: 2, Column: 4}},
+		{token.INT, token.Position{Line: 2, Column: 7}},
+		{token.EOF, token.Position{Line: 3, Column: 1}},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Pos != tt.expectedPos {
+			t.Fatalf("tests[%d] - position wrong. expected=%s, got=%s", i, tt.expectedPos, tok.Pos)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `// first
+let x = 1; // second
+x // third`
+
+	l := New(input)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+	}
+
+	expected := []token.Token{
+		{Type: token.COMMENT, Literal: "// first", Pos: token.Position{Line: 1, Column: 1}},
+		{Type: token.COMMENT, Literal: "// second", Pos: token.Position{Line: 2, Column: 12}},
+		{Type: token.COMMENT, Literal: "// third", Pos: token.Position{Line: 3, Column: 3}},
+	}
+
+	comments := l.Comments()
+	if len(comments) != len(expected) {
+		t.Fatalf("wrong number of comments. expected=%d, got=%d", len(expected), len(comments))
+	}
+	for i, c := range comments {
+		if c != expected[i] {
+			t.Errorf("comments[%d] wrong. expected=%+v, got=%+v", i, expected[i], c)
+		}
+	}
+}
+
+func TestUnterminatedString(t *testing.T) {
+	l := New(`let s = "abc`)
+
+	for _, expected := range []token.Token{
+		{Type: token.LET, Literal: "let", Pos: token.Position{Line: 1, Column: 1}},
+		{Type: token.IDENT, Literal: "s", Pos: token.Position{Line: 1, Column: 5}},
+		{Type: token.ASSIGN, Literal: "=", Pos: token.Position{Line: 1, Column: 7}},
+		{Type: token.ILLEGAL, Literal: `"abc`, Pos: token.Position{Line: 1, Column: 9}},
+		{Type: token.EOF, Literal: "", Pos: token.Position{Line: 1, Column: 13}},
+	} {
+		if tok := l.NextToken(); tok != expected {
+			t.Fatalf("wrong token. expected=%+v, got=%+v", expected, tok)
+		}
+	}
+}
+
+func TestOptionalIndexSpacing(t *testing.T) {
+	l := New(`xs?[0] c ?[1]`)
+
+	for _, expected := range []token.TokenType{
+		token.IDENT, token.OPTIONAL_INDEX, token.INT, token.RBRACKET,
+		token.IDENT, token.QUESTION, token.LBRACKET, token.INT, token.RBRACKET,
+		token.EOF,
+	} {
+		if tok := l.NextToken(); tok.Type != expected {
+			t.Fatalf("wrong token type. expected=%q, got=%q (%q)", expected, tok.Type, tok.Literal)
+		}
+	}
+}
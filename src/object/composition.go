@@ -0,0 +1,16 @@
+package object
+
+// Composition is the callable produced by f >> g. Calling it applies First
+// to the arguments and Second to that result.
+type Composition struct {
+	First  Object
+	Second Object
+}
+
+func (c *Composition) Type() ObjectType {
+	return COMPOSITION_OBJ
+}
+
+func (c *Composition) Inspect() string {
+	return c.First.Inspect() + " >> " + c.Second.Inspect()
+}
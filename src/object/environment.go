@@ -1,8 +1,16 @@
 package object
 
+import "sort"
+
 type Environment struct {
 	store map[string]Object
 	outer *Environment
+
+	strictIndexing bool
+	coroutine      *coroutine
+
+	loader *ModuleLoader
+	file   string
 }
 
 func NewEnvironment() *Environment {
@@ -16,6 +24,15 @@ func NewEnclosedEnvironment(outer *Environment) *Environment {
 	return env
 }
 
+// NewBlockEnvironment returns a scope for a block of the code running in
+// outer, such as a match arm. Unlike a function call's environment it
+// still belongs to outer's generator call, so yield works inside it.
+func NewBlockEnvironment(outer *Environment) *Environment {
+	env := NewEnclosedEnvironment(outer)
+	env.coroutine = outer.coroutine
+	return env
+}
+
 func (e *Environment) Get(name string) (Object, bool) {
 	obj, ok := e.store[name]
 	if !ok && e.outer != nil {
@@ -28,3 +45,83 @@ func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
 	return val
 }
+
+// Outer returns the environment e is enclosed in, or nil for the outermost
+// one.
+func (e *Environment) Outer() *Environment {
+	return e.outer
+}
+
+// Names returns the names bound in e itself, without those of the outer
+// environments, in sorted order.
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Yield suspends the generator whose body runs in e and hands val to the
+// caller advancing it. It reports false when e does not belong to a
+// generator call, e.g. inside a nested function literal.
+func (e *Environment) Yield(val Object) bool {
+	if e.coroutine == nil {
+		return false
+	}
+	e.coroutine.yield(val)
+	return true
+}
+
+// SetStrictIndexing makes out-of-range indexing and missing hash keys
+// evaluate to errors instead of null. The setting lives on the outermost
+// environment so every enclosed scope and closure shares it.
+func (e *Environment) SetStrictIndexing(strict bool) {
+	if e.outer != nil {
+		e.outer.SetStrictIndexing(strict)
+		return
+	}
+	e.strictIndexing = strict
+}
+
+func (e *Environment) StrictIndexing() bool {
+	if e.outer != nil {
+		return e.outer.StrictIndexing()
+	}
+	return e.strictIndexing
+}
+
+// SetModuleLoader enables import for code running in e. Like strict
+// indexing it is stored on the outermost environment.
+func (e *Environment) SetModuleLoader(loader *ModuleLoader) {
+	if e.outer != nil {
+		e.outer.SetModuleLoader(loader)
+		return
+	}
+	e.loader = loader
+}
+
+func (e *Environment) ModuleLoader() *ModuleLoader {
+	if e.outer != nil {
+		return e.outer.ModuleLoader()
+	}
+	return e.loader
+}
+
+// SetFile records the path of the source file evaluated in e, which
+// relative imports are resolved against.
+func (e *Environment) SetFile(path string) {
+	if e.outer != nil {
+		e.outer.SetFile(path)
+		return
+	}
+	e.file = path
+}
+
+func (e *Environment) File() string {
+	if e.outer != nil {
+		return e.outer.File()
+	}
+	return e.file
+}
@@ -0,0 +1,78 @@
+package object
+
+// Equal reports whether two objects have the same value.
+// Strings, arrays and hashes are compared structurally, while functions
+// and builtins are only equal to themselves.
+func Equal(left, right Object) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+
+	if left.Type() != right.Type() {
+		return false
+	}
+
+	switch left := left.(type) {
+	case *Integer:
+		return left.Value == right.(*Integer).Value
+	case *Boolean:
+		return left.Value == right.(*Boolean).Value
+	case *String:
+		return left.Value == right.(*String).Value
+	case *Null:
+		return true
+	case *Array:
+		return arrayEqual(left, right.(*Array))
+	case *Hash:
+		return hashEqual(left, right.(*Hash))
+	case *Range:
+		return *left == *right.(*Range)
+	case *Instance:
+		return instanceEqual(left, right.(*Instance))
+	default:
+		return left == right
+	}
+}
+
+func arrayEqual(left, right *Array) bool {
+	if len(left.Elements) != len(right.Elements) {
+		return false
+	}
+
+	for i, el := range left.Elements {
+		if !Equal(el, right.Elements[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func instanceEqual(left, right *Instance) bool {
+	if left.Struct != right.Struct {
+		return false
+	}
+
+	for i, value := range left.Values {
+		if !Equal(value, right.Values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func hashEqual(left, right *Hash) bool {
+	if len(left.Pairs) != len(right.Pairs) {
+		return false
+	}
+
+	for key, pair := range left.Pairs {
+		other, ok := right.Pairs[key]
+		if !ok {
+			return false
+		}
+		if !Equal(pair.Value, other.Value) {
+			return false
+		}
+	}
+	return true
+}
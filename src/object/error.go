@@ -1,7 +1,21 @@
 package object
 
+const (
+	ERROR          = "Error"
+	TYPE_ERROR     = "TypeError"
+	ARGUMENT_ERROR = "ArgumentError"
+	INDEX_ERROR    = "IndexError"
+	KEY_ERROR      = "KeyError"
+	NAME_ERROR     = "NameError"
+	VALUE_ERROR    = "ValueError"
+	MATCH_ERROR    = "MatchError"
+)
+
 type Error struct {
 	Message string
+	Kind    string
+	Stack   []string // call sites the error propagated through, innermost first
+	Value   Object   // the value given to throw, nil for runtime errors
 }
 
 func (e *Error) Type() ObjectType {
@@ -8,9 +8,11 @@ import (
 )
 
 type Function struct {
-	Parameters []*ast.Identifier
-	Body       *ast.BlockStatement
-	Env        *Environment
+	Parameters  []*ast.Identifier
+	Patterns    []ast.Pattern
+	Body        *ast.BlockStatement
+	Env         *Environment
+	IsGenerator bool
 }
 
 func (f *Function) Type() ObjectType {
@@ -26,6 +28,9 @@ func (f *Function) Inspect() string {
 	}
 
 	out.WriteString("fn")
+	if f.IsGenerator {
+		out.WriteString("*")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") {\n")
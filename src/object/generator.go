@@ -0,0 +1,104 @@
+package object
+
+import "runtime"
+
+// Generator is the result of calling a generator function. Its body runs on
+// a separate goroutine that hands control back and forth with whoever calls
+// Next, so only one side is ever running at a time.
+type Generator struct {
+	Function *Function
+
+	co    *coroutine
+	body  func() Object
+	state generatorState
+}
+
+type generatorState int
+
+const (
+	generatorCreated generatorState = iota
+	generatorRunning
+	generatorSuspended
+	generatorDone
+)
+
+// coroutine is the channel pair shared between a Generator and the
+// goroutine running its body. It is kept apart from the Generator so that
+// an abandoned generator can be collected and its goroutine released.
+type coroutine struct {
+	resume chan struct{}
+	yields chan Object
+}
+
+// NewGenerator returns a generator that evaluates body the first time it is
+// advanced. env must be the environment body runs in; Yield calls made on it
+// suspend the body until the generator is advanced again.
+func NewGenerator(fn *Function, env *Environment, body func() Object) *Generator {
+	co := &coroutine{resume: make(chan struct{}), yields: make(chan Object)}
+	env.coroutine = co
+
+	g := &Generator{Function: fn, co: co, body: body}
+	runtime.SetFinalizer(g, func(g *Generator) {
+		if g.state == generatorSuspended {
+			close(g.co.resume)
+		}
+	})
+	return g
+}
+
+func (g *Generator) Type() ObjectType {
+	return GENERATOR_OBJ
+}
+
+func (g *Generator) Inspect() string {
+	return "generator"
+}
+
+// Next resumes the body until it yields the next value or finishes. An
+// error raised by the body is returned as the final element. Advancing a
+// generator from its own body would wait for itself forever, so it
+// returns an error element instead.
+func (g *Generator) Next() (Object, bool) {
+	switch g.state {
+	case generatorDone:
+		return nil, false
+	case generatorRunning:
+		return &Error{Kind: VALUE_ERROR, Message: "generator already running"}, true
+	case generatorCreated:
+		g.state = generatorRunning
+		go g.co.run(g.body)
+	case generatorSuspended:
+		g.state = generatorRunning
+		g.co.resume <- struct{}{}
+	}
+
+	val, ok := <-g.co.yields
+	if !ok {
+		g.state = generatorDone
+		return nil, false
+	}
+	g.state = generatorSuspended
+	return val, true
+}
+
+// Iterate returns the generator itself, so it can only be consumed once.
+func (g *Generator) Iterate() Iterator {
+	return g
+}
+
+func (co *coroutine) run(body func() Object) {
+	result := body()
+	if result != nil && result.Type() == ERROR_OBJ {
+		// Handed over like a yielded value, so that the Next after it
+		// finds the body waiting to be resumed.
+		co.yield(result)
+	}
+	close(co.yields)
+}
+
+func (co *coroutine) yield(val Object) {
+	co.yields <- val
+	if _, ok := <-co.resume; !ok {
+		runtime.Goexit()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"sort"
 	"strings"
 )
 
@@ -33,7 +34,7 @@ func (h *Hash) Inspect() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for _, pair := range h.Pairs {
+	for _, pair := range h.SortedPairs() {
 		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
 	}
 
@@ -44,6 +45,38 @@ func (h *Hash) Inspect() string {
 	return out.String()
 }
 
+// SortedPairs returns the pairs ordered by key type and then by key value,
+// so that anything enumerating a hash produces reproducible output.
+func (h *Hash) SortedPairs() []HashPair {
+	pairs := make([]HashPair, 0, len(h.Pairs))
+	for _, pair := range h.Pairs {
+		pairs = append(pairs, pair)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return lessKey(pairs[i].Key, pairs[j].Key)
+	})
+
+	return pairs
+}
+
+func lessKey(left, right Object) bool {
+	if left.Type() != right.Type() {
+		return left.Type() < right.Type()
+	}
+
+	switch left := left.(type) {
+	case *Integer:
+		return left.Value < right.(*Integer).Value
+	case *String:
+		return left.Value < right.(*String).Value
+	case *Boolean:
+		return !left.Value && right.(*Boolean).Value
+	default:
+		return false
+	}
+}
+
 func (b *Boolean) HashKey() HashKey {
 	var value uint64
 
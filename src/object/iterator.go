@@ -0,0 +1,91 @@
+package object
+
+import "unicode/utf8"
+
+// Iterator yields the elements of a collection one at a time. Next returns
+// false once the iterator is exhausted; an *Error element means iteration
+// failed and the consumer should stop.
+type Iterator interface {
+	Next() (Object, bool)
+}
+
+// Iterable is implemented by objects that can be consumed element by element
+// without materialising them into an Array first.
+type Iterable interface {
+	Iterate() Iterator
+}
+
+type sliceIterator struct {
+	elements []Object
+	index    int
+}
+
+func (it *sliceIterator) Next() (Object, bool) {
+	if it.index >= len(it.elements) {
+		return nil, false
+	}
+	el := it.elements[it.index]
+	it.index++
+	return el, true
+}
+
+type stringIterator struct {
+	value  string
+	offset int // in bytes
+}
+
+func (it *stringIterator) Next() (Object, bool) {
+	if it.offset >= len(it.value) {
+		return nil, false
+	}
+	_, size := utf8.DecodeRuneInString(it.value[it.offset:])
+	ch := &String{Value: it.value[it.offset : it.offset+size]}
+	it.offset += size
+	return ch, true
+}
+
+type rangeIterator struct {
+	rng     *Range
+	current int64
+	done    bool
+}
+
+func (it *rangeIterator) Next() (Object, bool) {
+	if it.done || !it.rng.contains(it.current) {
+		return nil, false
+	}
+	value := &Integer{Value: it.current}
+	next := it.current + it.rng.Step
+	// Stepping past the largest or smallest integer wraps around.
+	if (it.rng.Step > 0) != (next > it.current) {
+		it.done = true
+	}
+	it.current = next
+	return value, true
+}
+
+// Iterate yields the elements of the array in order.
+func (ao *Array) Iterate() Iterator {
+	return &sliceIterator{elements: ao.Elements}
+}
+
+// Iterate yields each character (Unicode code point) of the string as a
+// one-character string, matching what indexing returns.
+func (s *String) Iterate() Iterator {
+	return &stringIterator{value: s.Value}
+}
+
+// Iterate yields the keys of the hash in the order of SortedPairs.
+func (h *Hash) Iterate() Iterator {
+	pairs := h.SortedPairs()
+	keys := make([]Object, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+	return &sliceIterator{elements: keys}
+}
+
+// Iterate yields the integers of the range lazily.
+func (r *Range) Iterate() Iterator {
+	return &rangeIterator{rng: r, current: r.Start}
+}
@@ -0,0 +1,76 @@
+package object
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Module is the value bound by import. Only names declared with export in
+// the module's source are visible through it.
+type Module struct {
+	Path    string
+	Exports map[string]Object
+}
+
+func (m *Module) Type() ObjectType {
+	return MODULE_OBJ
+}
+
+func (m *Module) Inspect() string {
+	names := make([]string, 0, len(m.Exports))
+	for name := range m.Exports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return "module " + m.Path + " { " + strings.Join(names, ", ") + " }"
+}
+
+// ModuleLoader reads imported files from FS and remembers every module
+// evaluated so far, so each file runs at most once per loader. Embedders
+// control what a script may import through the filesystem they pass in.
+type ModuleLoader struct {
+	FS fs.FS
+
+	// NewEnvironment creates the environment each module is evaluated in.
+	// Embedders set it to however they set up their root environment, so
+	// that modules see the same prelude and settings as the main script.
+	NewEnvironment func() *Environment
+
+	modules map[string]*Module
+	loading []string
+}
+
+func NewModuleLoader(fsys fs.FS) *ModuleLoader {
+	return &ModuleLoader{FS: fsys, NewEnvironment: NewEnvironment, modules: make(map[string]*Module)}
+}
+
+// Lookup returns the cached module loaded from path.
+func (ml *ModuleLoader) Lookup(path string) (*Module, bool) {
+	m, ok := ml.modules[path]
+	return m, ok
+}
+
+// Begin marks path as being evaluated. It reports false, together with the
+// chain of imports leading back to path, when path is already being
+// evaluated, i.e. when the import would be a cycle.
+func (ml *ModuleLoader) Begin(path string) ([]string, bool) {
+	for i, p := range ml.loading {
+		if p == path {
+			cycle := append([]string{}, ml.loading[i:]...)
+			return append(cycle, path), false
+		}
+	}
+	ml.loading = append(ml.loading, path)
+	return nil, true
+}
+
+// End finishes the evaluation started by Begin and caches its result when
+// m is not nil.
+func (ml *ModuleLoader) End(path string, m *Module) {
+	ml.loading = ml.loading[:len(ml.loading)-1]
+	if m != nil {
+		ml.modules[path] = m
+	}
+}
@@ -18,6 +18,14 @@ const (
 	BUILTIN_OBJ      = "BUILTIN"
 	ARRAY_OBJ        = "ARRAY"
 	HASH_OBJ         = "HASH"
+	RANGE_OBJ        = "RANGE"
+	GENERATOR_OBJ    = "GENERATOR"
+	COMPOSITION_OBJ  = "COMPOSITION"
+	STRUCT_OBJ       = "STRUCT"
+	INSTANCE_OBJ     = "INSTANCE"
+	MODULE_OBJ       = "MODULE"
+	QUOTE_OBJ        = "QUOTE"
+	MACRO_OBJ        = "MACRO"
 )
 
 type BuiltinFunction func(args ...Object) Object
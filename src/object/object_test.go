@@ -20,3 +20,51 @@ func TestStringHashKey(t *testing.T) {
 		t.Errorf("strings with same content have different hash keys")
 	}
 }
+
+func TestEqual(t *testing.T) {
+	one := &Integer{Value: 1}
+	fn := &Builtin{}
+
+	tests := []struct {
+		left     Object
+		right    Object
+		expected bool
+	}{
+		{&Integer{Value: 1}, &Integer{Value: 1}, true},
+		{&Integer{Value: 1}, &Integer{Value: 2}, false},
+		{&String{Value: "a"}, &String{Value: "a"}, true},
+		{&String{Value: "a"}, &String{Value: "b"}, false},
+		{&Boolean{Value: true}, &Boolean{Value: true}, true},
+		{&Null{}, &Null{}, true},
+		{&Integer{Value: 1}, &String{Value: "1"}, false},
+		{
+			&Array{Elements: []Object{one, &String{Value: "a"}}},
+			&Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "a"}}},
+			true,
+		},
+		{
+			&Array{Elements: []Object{one}},
+			&Array{Elements: []Object{one, one}},
+			false,
+		},
+		{
+			&Hash{Pairs: map[HashKey]HashPair{one.HashKey(): {Key: one, Value: &Array{Elements: []Object{one}}}}},
+			&Hash{Pairs: map[HashKey]HashPair{one.HashKey(): {Key: one, Value: &Array{Elements: []Object{&Integer{Value: 1}}}}}},
+			true,
+		},
+		{
+			&Hash{Pairs: map[HashKey]HashPair{one.HashKey(): {Key: one, Value: one}}},
+			&Hash{Pairs: map[HashKey]HashPair{}},
+			false,
+		},
+		{fn, fn, true},
+		{fn, &Builtin{}, false},
+	}
+
+	for i, tt := range tests {
+		if got := Equal(tt.left, tt.right); got != tt.expected {
+			t.Errorf("tests[%d] - Equal(%s, %s) wrong. expected=%t, got=%t",
+				i, tt.left.Inspect(), tt.right.Inspect(), tt.expected, got)
+		}
+	}
+}
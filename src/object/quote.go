@@ -0,0 +1,16 @@
+package object
+
+import "github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+
+// Quote holds an unevaluated piece of source code as returned by quote().
+type Quote struct {
+	Node ast.Node
+}
+
+func (q *Quote) Type() ObjectType {
+	return QUOTE_OBJ
+}
+
+func (q *Quote) Inspect() string {
+	return "QUOTE(" + q.Node.String() + ")"
+}
@@ -0,0 +1,94 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// Range is a lazy sequence of integers from Start towards End, moving by
+// Step. End itself is part of the range only when Inclusive is set.
+type Range struct {
+	Start     int64
+	End       int64
+	Step      int64
+	Inclusive bool
+}
+
+func (r *Range) Type() ObjectType {
+	return RANGE_OBJ
+}
+
+func (r *Range) Inspect() string {
+	var out bytes.Buffer
+
+	out.WriteString(fmt.Sprintf("%d", r.Start))
+	if r.Inclusive {
+		out.WriteString("..")
+	} else {
+		out.WriteString("..<")
+	}
+	out.WriteString(fmt.Sprintf("%d", r.End))
+	if r.Step != 1 {
+		out.WriteString(fmt.Sprintf(" step %d", r.Step))
+	}
+
+	return out.String()
+}
+
+// Len returns the number of integers the range yields, or math.MaxInt64
+// if there are more.
+func (r *Range) Len() int64 {
+	if !r.contains(r.Start) {
+		return 0
+	}
+
+	span, stride := r.offset(r.End), r.stride()
+	count := span / stride
+	if count >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	if span%stride != 0 || r.Inclusive {
+		count++
+	}
+	return int64(count)
+}
+
+// Contains reports whether the range yields value.
+func (r *Range) Contains(value int64) bool {
+	if !r.contains(value) {
+		return false
+	}
+	return r.offset(value)%r.stride() == 0
+}
+
+func (r *Range) contains(value int64) bool {
+	switch {
+	case r.Step > 0 && r.Inclusive:
+		return value >= r.Start && value <= r.End
+	case r.Step > 0:
+		return value >= r.Start && value < r.End
+	case r.Inclusive:
+		return value <= r.Start && value >= r.End
+	default:
+		return value <= r.Start && value > r.End
+	}
+}
+
+// offset is the distance from Start to value in the direction of Step,
+// which must not be negative. It is unsigned so that ranges spanning more
+// than half of the integers do not overflow.
+func (r *Range) offset(value int64) uint64 {
+	if r.Step > 0 {
+		return uint64(value) - uint64(r.Start)
+	}
+	return uint64(r.Start) - uint64(value)
+}
+
+// stride is the absolute value of Step.
+func (r *Range) stride() uint64 {
+	if r.Step > 0 {
+		return uint64(r.Step)
+	}
+	return uint64(-r.Step)
+}
@@ -0,0 +1,84 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Struct is a type declared with struct Name { fields }. Calling it
+// constructs an Instance with one value per field, in declaration order.
+type Struct struct {
+	Name    string
+	Fields  []string
+	Methods map[string]*Function
+}
+
+func (s *Struct) Type() ObjectType {
+	return STRUCT_OBJ
+}
+
+func (s *Struct) Inspect() string {
+	return "struct " + s.Name + " { " + strings.Join(s.Fields, ", ") + " }"
+}
+
+// FieldIndex returns the position of the named field, or -1.
+func (s *Struct) FieldIndex(name string) int {
+	for i, field := range s.Fields {
+		if field == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Instance is a value of a struct type. Its set of fields is fixed by the
+// struct declaration.
+type Instance struct {
+	Struct *Struct
+	Values []Object
+}
+
+func (i *Instance) Type() ObjectType {
+	return INSTANCE_OBJ
+}
+
+func (i *Instance) Inspect() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for idx, field := range i.Struct.Fields {
+		fields = append(fields, field+": "+i.Values[idx].Inspect())
+	}
+
+	out.WriteString(i.Struct.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Get returns the value of the named field.
+func (i *Instance) Get(name string) (Object, bool) {
+	idx := i.Struct.FieldIndex(name)
+	if idx < 0 {
+		return nil, false
+	}
+	return i.Values[idx], true
+}
+
+// BoundMethod is a method looked up on an instance. Calling it runs Method
+// with self bound to Receiver.
+type BoundMethod struct {
+	Receiver *Instance
+	Name     string
+	Method   *Function
+}
+
+func (bm *BoundMethod) Type() ObjectType {
+	return FUNCTION_OBJ
+}
+
+func (bm *BoundMethod) Inspect() string {
+	return "method " + bm.Receiver.Struct.Name + "." + bm.Name
+}
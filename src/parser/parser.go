@@ -16,6 +16,17 @@ type Parser struct {
 
 	curToken  token.Token
 	peekToken token.Token
+	// lookahead buffers tokens read past peekToken by peekTokenAfter.
+	lookahead []token.Token
+
+	// depth counts the blocks enclosing curToken; export is only allowed
+	// at depth 0.
+	depth int
+
+	// inGuard is set while parsing a match guard, where `=>` ends the
+	// guard instead of starting an arrow function. It is cleared inside
+	// brackets, which the guard cannot end in.
+	inGuard bool
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
@@ -43,6 +54,10 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
+	p.registerPrefix(token.YIELD, p.parseYieldExpression)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
@@ -53,8 +68,20 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.LT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.GT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.IN, p.parseInfixExpression)
+	p.registerInfix(token.DOTDOT, p.parseRangeExpression)
+	p.registerInfix(token.DOTDOT_LT, p.parseRangeExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
+	p.registerInfix(token.QUESTION, p.parseConditionalExpression)
+	p.registerInfix(token.NULLISH, p.parseInfixExpression)
+	p.registerInfix(token.PIPE, p.parseInfixExpression)
+	p.registerInfix(token.COMPOSE, p.parseInfixExpression)
+	p.registerInfix(token.OPTIONAL_DOT, p.parseOptionalIndexExpression)
+	p.registerInfix(token.OPTIONAL_INDEX, p.parseOptionalIndexExpression)
 
 	p.nextToken()
 	p.nextToken()
@@ -84,6 +111,7 @@ func (p *Parser) ParseProgram() *ast.Program {
 		}
 		p.nextToken()
 	}
+	program.Comments = p.l.Comments()
 
 	return program
 }
@@ -146,6 +174,28 @@ func (p *Parser) parseStatement() ast.Statement {
 			p.logger.Error("[parser] failed to parse return statement")
 		}
 		return stmt
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.STRUCT:
+		if stmt := p.parseStructStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.IMPL:
+		if stmt := p.parseImplStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.IMPORT:
+		if stmt := p.parseImportStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.EXPORT:
+		if stmt := p.parseExportStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -171,15 +221,20 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
 	block.Statements = []ast.Statement{}
 
+	p.depth++
+	defer func() { p.depth-- }()
+	defer p.leaveGuard()()
+
 	p.nextToken()
 
-	for !p.curTokenIs(token.RBRACE) {
+	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
 		stmt := p.parseStatement()
 		if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
 		}
 		p.nextToken()
 	}
+	block.Rbrace = p.curToken.Pos
 	return block
 }
 
@@ -191,13 +246,19 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 
 	stmt := &ast.LetStatement{Token: p.curToken}
 
-	if !p.expectPeek(token.IDENT) {
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		stmt.Pattern = p.parsePattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	} else if !p.expectPeek(token.IDENT) {
 		p.notExpectedToken(token.IDENT, p.curToken.Type)
 		return nil
+	} else {
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	}
 
-	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-
 	if !p.expectPeek(token.ASSIGN) {
 		p.notExpectedToken(token.ASSIGN, p.curToken.Type)
 		return nil
@@ -212,7 +273,7 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 
 	stmt.Value = p.parseExpression(LOWEST)
 
-	for !p.curTokenIs(token.SEMICOLON) {
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
@@ -237,21 +298,246 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 
 	stmt.ReturnValue = p.parseExpression(LOWEST)
 
-	for !p.curTokenIs(token.SEMICOLON) {
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
 	return stmt
 }
 
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.STRING) {
+		p.notExpectedToken(token.STRING, p.peekToken.Type)
+		return nil
+	}
+	stmt.Path = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.AS) {
+		p.notExpectedToken(token.AS, p.peekToken.Type)
+		return nil
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		p.notExpectedToken(token.IDENT, p.peekToken.Type)
+		return nil
+	}
+	stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseExportStatement() *ast.ExportStatement {
+	stmt := &ast.ExportStatement{Token: p.curToken}
+
+	if p.depth > 0 {
+		p.errors = append(p.errors, "export is only allowed at the top level")
+		return nil
+	}
+
+	p.nextToken()
+	switch p.curToken.Type {
+	case token.LET:
+		if let := p.parseLetStatement(); let != nil {
+			stmt.Statement = let
+		}
+	case token.STRUCT:
+		if st := p.parseStructStatement(); st != nil {
+			stmt.Statement = st
+		}
+	default:
+		p.errors = append(p.errors, fmt.Sprintf("expected let or struct after export, got %s", p.curToken.Type))
+	}
+
+	if stmt.Statement == nil {
+		return nil
+	}
+	return stmt
+}
+
+func (p *Parser) parseStructStatement() *ast.StructStatement {
+	stmt := &ast.StructStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		p.notExpectedToken(token.IDENT, p.peekToken.Type)
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		p.notExpectedToken(token.LBRACE, p.peekToken.Type)
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			p.notExpectedToken(token.IDENT, p.peekToken.Type)
+			return nil
+		}
+		if seen[p.curToken.Literal] {
+			p.errors = append(p.errors, fmt.Sprintf("duplicate field %s in struct %s", p.curToken.Literal, stmt.Name.Value))
+			return nil
+		}
+		seen[p.curToken.Literal] = true
+		stmt.Fields = append(stmt.Fields, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			p.notExpectedToken(token.COMMA, p.peekToken.Type)
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		p.notExpectedToken(token.RBRACE, p.peekToken.Type)
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseImplStatement parses impl Name { method(params) { body } ... }.
+func (p *Parser) parseImplStatement() *ast.ImplStatement {
+	stmt := &ast.ImplStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		p.notExpectedToken(token.IDENT, p.peekToken.Type)
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		p.notExpectedToken(token.LBRACE, p.peekToken.Type)
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			p.notExpectedToken(token.IDENT, p.peekToken.Type)
+			return nil
+		}
+		method := &ast.StructMethod{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+		method.Function = &ast.FunctionLiteral{Token: token.Token{Type: token.FUNCTION, Literal: "fn", Pos: p.curToken.Pos}}
+
+		if !p.expectPeek(token.LPAREN) {
+			p.notExpectedToken(token.LPAREN, p.peekToken.Type)
+			return nil
+		}
+		method.Function.Parameters = p.parseFunctionParameters(method.Function)
+
+		if !p.expectPeek(token.LBRACE) {
+			p.notExpectedToken(token.LBRACE, p.peekToken.Type)
+			return nil
+		}
+		method.Function.Body = p.parseBlockStatement()
+
+		stmt.Methods = append(stmt.Methods, method)
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		p.notExpectedToken(token.RBRACE, p.peekToken.Type)
+		return nil
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseIdentifier() ast.Expression {
 	p.logger.WithFields(logrus.Fields{
 		"current_token": p.curToken,
 	}).Debug("[parser] parseIdentifier")
+
+	if p.peekTokenIs(token.ARROW) && !p.inGuard {
+		lit := p.newArrowFunction()
+		lit.Parameters = []*ast.Identifier{{Token: p.curToken, Value: p.curToken.Literal}}
+		return p.parseArrowFunctionBody(lit)
+	}
+
 	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+// newArrowFunction starts the FunctionLiteral an arrow function desugars
+// to, so x => x * 2 prints and evaluates like fn(x) { x * 2 }.
+func (p *Parser) newArrowFunction() *ast.FunctionLiteral {
+	return &ast.FunctionLiteral{
+		Token:   token.Token{Type: token.FUNCTION, Literal: "fn", Pos: p.curToken.Pos},
+		IsArrow: true,
+	}
+}
+
+// parseArrowFunctionBody parses the body after the parameters, with the
+// peek token at `=>`. A block is used as is; a single expression becomes
+// the only statement of the body.
+func (p *Parser) parseArrowFunctionBody(lit *ast.FunctionLiteral) ast.Expression {
+	if !p.expectPeek(token.ARROW) {
+		p.notExpectedToken(token.ARROW, p.peekToken.Type)
+		return nil
+	}
+
+	if p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		lit.Body = p.parseBlockStatement()
+		return lit
+	}
+
+	tok := p.curToken
+	p.nextToken()
+	stmt := &ast.ExpressionStatement{Token: p.curToken, Expression: p.parseExpression(LOWEST)}
+	lit.Body = &ast.BlockStatement{Token: tok, Statements: []ast.Statement{stmt}}
+
+	return lit
+}
+
+// isArrowParameterList reports whether the parenthesis at curToken opens
+// the parameter list of an arrow function, i.e. whether its matching ')'
+// is followed by `=>`.
+func (p *Parser) isArrowParameterList() bool {
+	if p.inGuard {
+		return false
+	}
+
+	depth := 1
+	tok := p.peekToken
+	for i := 1; tok.Type != token.EOF; i++ {
+		switch tok.Type {
+		case token.LPAREN, token.LBRACKET, token.LBRACE, token.OPTIONAL_INDEX:
+			depth++
+		case token.RPAREN, token.RBRACKET, token.RBRACE:
+			depth--
+		}
+		if depth == 0 {
+			return tok.Type == token.RPAREN && p.peekTokenAfter(i).Type == token.ARROW
+		}
+		tok = p.peekTokenAfter(i)
+	}
+
+	return false
+}
+
+func (p *Parser) parseFunctionParameters(lit *ast.FunctionLiteral) []*ast.Identifier {
 	identifier := []*ast.Identifier{}
 
 	if p.peekTokenIs(token.RPAREN) {
@@ -261,14 +547,12 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 
 	p.nextToken()
 
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifier = append(identifier, ident)
+	identifier = append(identifier, p.parseFunctionParameter(lit, len(identifier)))
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
-		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		identifier = append(identifier, ident)
+		identifier = append(identifier, p.parseFunctionParameter(lit, len(identifier)))
 	}
 
 	if !p.expectPeek(token.RPAREN) {
@@ -279,7 +563,30 @@ func (p *Parser) parseFunctionParameters() []*ast.Identifier {
 	return identifier
 }
 
+// parseFunctionParameter parses the parameter at index idx. A destructured
+// parameter is recorded in lit.Patterns and gets a placeholder identifier
+// spelled like the pattern, which can never clash with a real name.
+func (p *Parser) parseFunctionParameter(lit *ast.FunctionLiteral, idx int) *ast.Identifier {
+	if !p.curTokenIs(token.LBRACKET) && !p.curTokenIs(token.LBRACE) {
+		return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	tok := p.curToken
+	pattern := p.parsePattern()
+	if pattern == nil {
+		return &ast.Identifier{Token: tok, Value: tok.Literal}
+	}
+
+	for len(lit.Patterns) < idx {
+		lit.Patterns = append(lit.Patterns, nil)
+	}
+	lit.Patterns = append(lit.Patterns, pattern)
+
+	return &ast.Identifier{Token: tok, Value: pattern.String()}
+}
+
 func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	defer p.leaveGuard()()
 	list := []ast.Expression{}
 
 	if p.peekTokenIs(end) {
@@ -325,14 +632,20 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 func (p *Parser) parseFunctionLiteral() ast.Expression {
 	lit := &ast.FunctionLiteral{Token: p.curToken}
 
+	if p.peekTokenIs(token.ASTERISK) {
+		p.nextToken()
+		lit.IsGenerator = true
+	}
+
 	if !p.expectPeek(token.LPAREN) {
 		p.notExpectedToken(token.LPAREN, p.curToken.Type)
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	lit.Parameters = p.parseFunctionParameters(lit)
 
 	if !p.expectPeek(token.LBRACE) {
+		p.notExpectedToken(token.LBRACE, p.peekToken.Type)
 		return nil
 	}
 
@@ -341,6 +654,44 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return lit
 }
 
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	lit := &ast.MacroLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		p.notExpectedToken(token.LPAREN, p.peekToken.Type)
+		return nil
+	}
+
+	params := &ast.FunctionLiteral{}
+	lit.Parameters = p.parseFunctionParameters(params)
+	if params.Patterns != nil {
+		p.errors = append(p.errors, "macro parameters cannot be destructured")
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		p.notExpectedToken(token.LBRACE, p.peekToken.Type)
+		return nil
+	}
+
+	lit.Body = p.parseBlockStatement()
+
+	return lit
+}
+
+func (p *Parser) parseYieldExpression() ast.Expression {
+	exp := &ast.YieldExpression{Token: p.curToken}
+
+	if p.peekTokenIs(token.SEMICOLON) || p.peekTokenIs(token.RBRACE) {
+		return exp
+	}
+
+	p.nextToken()
+	exp.Value = p.parseExpression(LOWEST)
+
+	return exp
+}
+
 func (p *Parser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
@@ -348,6 +699,7 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.curToken}
 	array.Elements = p.parseExpressionList(token.RBRACKET)
+	array.Rbracket = p.curToken.Pos
 	return array
 }
 
@@ -397,7 +749,35 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseRangeExpression(start ast.Expression) ast.Expression {
+	exp := &ast.RangeExpression{
+		Token:     p.curToken,
+		Start:     start,
+		Inclusive: p.curTokenIs(token.DOTDOT),
+	}
+
+	p.nextToken()
+	exp.End = p.parseExpression(RANGE)
+
+	// step is not a keyword, so that it stays usable as a name; it only
+	// has a meaning right after the end of a range.
+	if p.peekTokenIs(token.IDENT) && p.peekToken.Literal == "step" {
+		p.nextToken()
+		p.nextToken()
+		exp.Step = p.parseExpression(RANGE)
+	}
+
+	return exp
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	if p.isArrowParameterList() {
+		lit := p.newArrowFunction()
+		lit.Parameters = p.parseFunctionParameters(lit)
+		return p.parseArrowFunctionBody(lit)
+	}
+	defer p.leaveGuard()()
+
 	p.nextToken()
 
 	exp := p.parseExpression(LOWEST)
@@ -435,6 +815,17 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	if p.peekTokenIs(token.ELSE) {
 		p.nextToken()
 
+		if p.peekTokenIs(token.IF) {
+			p.nextToken()
+			tok := p.curToken
+			stmt := &ast.ExpressionStatement{Token: tok, Expression: p.parseIfExpression()}
+			if stmt.Expression == nil {
+				return nil
+			}
+			expression.Alternative = &ast.BlockStatement{Token: tok, Statements: []ast.Statement{stmt}}
+			return expression
+		}
+
 		if !p.expectPeek(token.LBRACE) {
 			p.notExpectedToken(token.LBRACE, p.curToken.Type)
 			return nil
@@ -446,9 +837,169 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	expression := &ast.ConditionalExpression{Token: p.curToken, Condition: condition}
+
+	p.nextToken()
+	expression.Consequence = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.COLON) {
+		p.notExpectedToken(token.COLON, p.peekToken.Type)
+		return nil
+	}
+
+	// Parsing the alternative below TERNARY makes a ? b : c ? d : e group
+	// to the right.
+	p.nextToken()
+	expression.Alternative = p.parseExpression(LOWEST)
+
+	return expression
+}
+
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		p.notExpectedToken(token.LBRACE, p.peekToken.Type)
+		return nil
+	}
+
+	expression.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENT) {
+				p.notExpectedToken(token.IDENT, p.peekToken.Type)
+				return nil
+			}
+			expression.CatchParam = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			if !p.expectPeek(token.RPAREN) {
+				p.notExpectedToken(token.RPAREN, p.peekToken.Type)
+				return nil
+			}
+		}
+
+		if !p.expectPeek(token.LBRACE) {
+			p.notExpectedToken(token.LBRACE, p.peekToken.Type)
+			return nil
+		}
+
+		expression.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			p.notExpectedToken(token.LBRACE, p.peekToken.Type)
+			return nil
+		}
+
+		expression.Finally = p.parseBlockStatement()
+	}
+
+	if expression.Catch == nil && expression.Finally == nil {
+		p.errors = append(p.errors, "expected catch or finally after try block")
+		return nil
+	}
+
+	return expression
+}
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		p.notExpectedToken(token.LPAREN, p.peekToken.Type)
+		return nil
+	}
+
+	p.nextToken()
+	expression.Value = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		p.notExpectedToken(token.RPAREN, p.peekToken.Type)
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		p.notExpectedToken(token.LBRACE, p.peekToken.Type)
+		return nil
+	}
+	defer p.leaveGuard()()
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		expression.Arms = append(expression.Arms, arm)
+
+		if p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+		} else if !p.peekTokenIs(token.RBRACE) && arm.Body.Token.Type != token.LBRACE {
+			p.notExpectedToken(token.COMMA, p.peekToken.Type)
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		p.notExpectedToken(token.RBRACE, p.peekToken.Type)
+		return nil
+	}
+
+	if len(expression.Arms) == 0 {
+		p.errors = append(p.errors, "match expression must have at least one arm")
+		return nil
+	}
+
+	return expression
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{Pattern: p.parsePattern()}
+	if arm.Pattern == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		p.nextToken()
+		inGuard := p.inGuard
+		p.inGuard = true
+		arm.Guard = p.parseExpression(LOWEST)
+		p.inGuard = inGuard
+	}
+
+	if !p.expectPeek(token.ARROW) {
+		p.notExpectedToken(token.ARROW, p.peekToken.Type)
+		return nil
+	}
+	arrow := p.curToken
+
+	if p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		arm.Body = p.parseBlockStatement()
+		return arm
+	}
+
+	p.nextToken()
+	stmt := &ast.ExpressionStatement{Token: p.curToken, Expression: p.parseExpression(LOWEST)}
+	arm.Body = &ast.BlockStatement{Token: arrow, Statements: []ast.Statement{stmt}}
+
+	return arm
+}
+
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
+	exp.OptionalChain = continuesOptionalChain(function)
 	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	exp.Rparen = p.curToken.Pos
 	return exp
 }
 
@@ -478,12 +1029,95 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 }
 
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	defer p.leaveGuard()()
+	tok := p.curToken
+
+	p.nextToken()
+	if p.curTokenIs(token.COLON) {
+		return p.parseSliceExpression(tok, left, nil)
+	}
+
+	index := p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		return p.parseSliceExpression(tok, left, index)
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return &ast.IndexExpression{Token: tok, Left: left, Index: index, OptionalChain: continuesOptionalChain(left)}
+}
+
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Left: left, OptionalChain: continuesOptionalChain(left)}
+
+	if !p.expectPeek(token.IDENT) {
+		p.notExpectedToken(token.IDENT, p.peekToken.Type)
+		return nil
+	}
+	exp.Member = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp
+}
+
+// parseOptionalIndexExpression parses a?.name, a shorthand for a?["name"],
+// and a?[index].
+func (p *Parser) parseOptionalIndexExpression(left ast.Expression) ast.Expression {
+	exp := &ast.OptionalIndexExpression{Token: p.curToken, Left: left}
 
+	if p.curTokenIs(token.OPTIONAL_DOT) {
+		if !p.expectPeek(token.IDENT) {
+			p.notExpectedToken(token.IDENT, p.peekToken.Type)
+			return nil
+		}
+		exp.Index = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+		return exp
+	}
+
+	defer p.leaveGuard()()
 	p.nextToken()
 	exp.Index = p.parseExpression(LOWEST)
 
 	if !p.expectPeek(token.RBRACKET) {
+		p.notExpectedToken(token.RBRACKET, p.peekToken.Type)
+		return nil
+	}
+
+	return exp
+}
+
+// continuesOptionalChain reports whether a postfix operator applied to left
+// belongs to an optional chain, i.e. whether left is a ?. or ?[ link or
+// itself part of such a chain.
+func continuesOptionalChain(left ast.Expression) bool {
+	switch left := left.(type) {
+	case *ast.OptionalIndexExpression:
+		return true
+	case *ast.IndexExpression:
+		return left.OptionalChain
+	case *ast.MemberExpression:
+		return left.OptionalChain
+	case *ast.CallExpression:
+		return left.OptionalChain
+	case *ast.SliceExpression:
+		return left.OptionalChain
+	}
+	return false
+}
+
+func (p *Parser) parseSliceExpression(tok token.Token, left, start ast.Expression) ast.Expression {
+	exp := &ast.SliceExpression{Token: tok, Left: left, Start: start, OptionalChain: continuesOptionalChain(left)}
+
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		exp.End = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		p.notExpectedToken(token.RBRACKET, p.peekToken.Type)
 		return nil
 	}
 
@@ -491,6 +1125,7 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 }
 
 func (p *Parser) parseHashLiteral() ast.Expression {
+	defer p.leaveGuard()()
 	hash := &ast.HashLiteral{Token: p.curToken}
 	hash.Pairs = make(map[ast.Expression]ast.Expression)
 
@@ -515,6 +1150,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	if !p.expectPeek(token.RBRACE) {
 		return nil
 	}
+	hash.Rbrace = p.curToken.Pos
 
 	return hash
 }
@@ -16,8 +16,13 @@ type (
 const (
 	_ int = iota
 	LOWEST
+	TERNARY     // a ? b : c
+	COALESCE    // a ?? b
+	PIPELINE    // xs |> f(y)
+	COMPOSITION // f >> g
 	EQUALS      // ==
 	LESSGREATER // > or <
+	RANGE       // 0..10
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // -X or !X
@@ -26,23 +31,49 @@ const (
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.LPAREN:   CALL,
-	token.LBRACKET: INDEX,
+	token.QUESTION:       TERNARY,
+	token.PIPE:           PIPELINE,
+	token.NULLISH:        COALESCE,
+	token.COMPOSE:        COMPOSITION,
+	token.EQ:             EQUALS,
+	token.NOT_EQ:         EQUALS,
+	token.LT:             LESSGREATER,
+	token.GT:             LESSGREATER,
+	token.LT_EQ:          LESSGREATER,
+	token.GT_EQ:          LESSGREATER,
+	token.IN:             LESSGREATER,
+	token.DOTDOT:         RANGE,
+	token.DOTDOT_LT:      RANGE,
+	token.PLUS:           SUM,
+	token.MINUS:          SUM,
+	token.SLASH:          PRODUCT,
+	token.ASTERISK:       PRODUCT,
+	token.LPAREN:         CALL,
+	token.LBRACKET:       INDEX,
+	token.DOT:            INDEX,
+	token.OPTIONAL_DOT:   INDEX,
+	token.OPTIONAL_INDEX: INDEX,
+}
+
+// Precedence returns the binding power of the infix or postfix operator t,
+// or LOWEST if t is not one.
+func Precedence(t token.TokenType) int {
+	if p, ok := precedences[t]; ok {
+		return p
+	}
+	return LOWEST
 }
 
 var strPrecedences = []string{
 	"ILLEGAL",
 	"LOWEST",
+	"TERNARY",
+	"COALESCE",
+	"PIPELINE",
+	"COMPOSITION",
 	"EQUALS",
 	"LESSGREATER",
+	"RANGE",
 	"SUM",
 	"PRODUCT",
 	"PREFIX",
@@ -56,7 +87,12 @@ func (p *Parser) peekError(t token.TokenType) {
 
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
-	p.peekToken = p.l.NextToken()
+	if len(p.lookahead) > 0 {
+		p.peekToken = p.lookahead[0]
+		p.lookahead = p.lookahead[1:]
+	} else {
+		p.peekToken = p.l.NextToken()
+	}
 
 	p.logger.WithFields(logrus.Fields{
 		"currentToken": p.curToken.Literal,
@@ -64,6 +100,15 @@ func (p *Parser) nextToken() {
 	}).Debug("[parser] nextToken")
 }
 
+// peekTokenAfter returns the token n positions after peekToken without
+// consuming anything.
+func (p *Parser) peekTokenAfter(n int) token.Token {
+	for len(p.lookahead) < n {
+		p.lookahead = append(p.lookahead, p.l.NextToken())
+	}
+	return p.lookahead[n-1]
+}
+
 func (p *Parser) curTokenIs(t token.TokenType) bool {
 	return p.curToken.Type == t
 }
@@ -113,3 +158,12 @@ func (p *Parser) curPrecedence() int {
 	}
 	return LOWEST
 }
+
+// leaveGuard clears inGuard until the returned function is called, for
+// parsing the inside of brackets in a match guard, e.g. the arguments of
+// a call, where `=>` starts an arrow function again.
+func (p *Parser) leaveGuard() func() {
+	inGuard := p.inGuard
+	p.inGuard = false
+	return func() { p.inGuard = inGuard }
+}
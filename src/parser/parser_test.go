@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
@@ -129,6 +130,37 @@ func TestParserIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestParsingSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a[1:3]", "(a[1:3])"},
+		{"a[:-1]", "(a[:(-1)])"},
+		{"a[2:]", "(a[2:])"},
+		{"a[:]", "(a[:])"},
+		{"a[i + 1:len(a)][0]", "((a[(i + 1):len(a)])[0])"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+
+		switch stmt.Expression.(type) {
+		case *ast.SliceExpression, *ast.IndexExpression:
+		default:
+			t.Errorf("exp not *ast.SliceExpression. got=%T", stmt.Expression)
+		}
+	}
+}
+
 func TestParsingArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 
@@ -235,6 +267,303 @@ func TestFunctionParameterParsing(t *testing.T) {
 	}
 }
 
+func TestGeneratorParsing(t *testing.T) {
+	input := `fn*(x) { yield x; yield; yield x + 1 }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	function, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T", stmt.Expression)
+	}
+
+	if !function.IsGenerator {
+		t.Fatalf("function.IsGenerator is not true")
+	}
+
+	if len(function.Body.Statements) != 3 {
+		t.Fatalf("function.Body.Statements has not 3 statements. got=%d", len(function.Body.Statements))
+	}
+
+	expected := []string{"yield x", "yield", "yield (x + 1)"}
+	for i, s := range function.Body.Statements {
+		exp, ok := s.(*ast.ExpressionStatement).Expression.(*ast.YieldExpression)
+		if !ok {
+			t.Fatalf("statement %d is not ast.YieldExpression. got=%T", i, s)
+		}
+		if exp.String() != expected[i] {
+			t.Errorf("statement %d wrong. expected=%q, got=%q", i, expected[i], exp.String())
+		}
+	}
+}
+
+func TestTryExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { x } catch (e) { y }`, "try x catch(e) y"},
+		{`try { x } catch { y } finally { z }`, "try x catch y finally z"},
+		{`try { x } finally { z }`, "try x finally z"},
+		{`throw x + 1;`, "throw (x + 1);"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	p := New(lexer.New(`try { x }`))
+	p.ParseProgram()
+	if len(p.Errors()) == 0 {
+		t.Errorf("expected an error for try without catch or finally")
+	}
+}
+
+func TestMatchExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (x) { 0 => "zero", -1 => "minus", _ => "other" }`, `match (x) { 0 => zero, (-1) => minus, _ => other }`},
+		{`match (xs) { [] => 0, [a, ...rest] => a }`, "match (xs) { [] => 0, [a, ...rest] => a }"},
+		{`match (p) { {"name": n, age} => n }`, "match (p) { {name: n, age: age} => n }"},
+		{`match (v) { n: INTEGER if n > 1 => n, _: STRING => 0 }`, "match (v) { n: INTEGER if (n > 1) => n, _: STRING => 0 }"},
+		{`match (v) { true => { let a = 1; a } _ => 2 }`, "match (v) { true => let a = 1;a, _ => 2 }"},
+		{`match (2) { a if any(xs, y => y > a) => "big", _ => "small" }`, "match (2) { a if any(xs, fn(y)(y > a)) => big, _ => small }"},
+		{`match (v) { a if (f(y => y)) => 1 }`, "match (v) { a if f(fn(y)y) => 1 }"},
+		{`match (v) { a if [y => y][0](a) => 1 }`, "match (v) { a if ([fn(y)y][0])(a) => 1 }"},
+		{`match (v) { a if match (a) { b if b => true } => f(y => y) }`, "match (v) { a if match (a) { b if b => true } => f(fn(y)y) }"},
+		{`match (v) { a if match (a) { b if b => true } == c => 1 }`, "match (v) { a if (match (a) { b if b => true } == c) => 1 }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	for _, input := range []string{`match (x) {}`, `match (x) { [...r, a] => 1 }`, `match (x) { 1 + 2 => 1 }`} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected a parse error for %s", input)
+		}
+	}
+}
+
+func TestElseIfParsing(t *testing.T) {
+	input := `if (x < y) { x } else if (x > y) { y } else { z }`
+
+	program := New(lexer.New(input)).ParseProgram()
+	exp := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+
+	if len(exp.Alternative.Statements) != 1 {
+		t.Fatalf("alternative is not 1 statement. got=%d", len(exp.Alternative.Statements))
+	}
+	nested, ok := exp.Alternative.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+	if !ok {
+		t.Fatalf("alternative is not *ast.IfExpression. got=%T", exp.Alternative.Statements[0])
+	}
+	if nested.Alternative == nil || nested.Alternative.String() != "z" {
+		t.Errorf("nested alternative wrong. got=%v", nested.Alternative)
+	}
+}
+
+func TestOptionalChainParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []bool // OptionalChain of each postfix expression, outermost first
+	}{
+		{`a.b.c`, []bool{false, false}},
+		{`a?.b.c`, []bool{true}},
+		{`a?.b.c[0](x)[1:]`, []bool{true, true, true, true}},
+		{`a.b?.c.d`, []bool{true, false}},
+		{`f(a?.b)`, []bool{false}},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		got := []bool{}
+		exp := program.Statements[0].(*ast.ExpressionStatement).Expression
+		for exp != nil {
+			switch e := exp.(type) {
+			case *ast.IndexExpression:
+				got, exp = append(got, e.OptionalChain), e.Left
+			case *ast.MemberExpression:
+				got, exp = append(got, e.OptionalChain), e.Left
+			case *ast.CallExpression:
+				got, exp = append(got, e.OptionalChain), e.Function
+			case *ast.SliceExpression:
+				got, exp = append(got, e.OptionalChain), e.Left
+			case *ast.OptionalIndexExpression:
+				exp = e.Left
+			default:
+				exp = nil
+			}
+		}
+
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("wrong OptionalChain flags for %s. expected=%v, got=%v", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestArrowFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`x => x * 2`, "fn(x)(x * 2)"},
+		{`(a, b) => a + b`, "fn(a, b)(a + b)"},
+		{`() => 1`, "fn()1"},
+		{`(x) => { let y = x; y }`, "fn(x)let y = x;y"},
+		{`([a, b]) => a`, "fn([a, b])a"},
+		{`map(xs, x => x + 1)`, "map(xs, fn(x)(x + 1))"},
+		{`(a + b) * c`, "((a + b) * c)"},
+		{`(f(a, b))`, "f(a, b)"},
+		{`(a) + (b)`, "(a + b)"},
+		{`x => y => x + y`, "fn(x)fn(y)(x + y)"},
+		{`match (v) { n if ok => n }`, "match (v) { n if ok => n }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	program := New(lexer.New(`(a, b) => a + b`)).ParseProgram()
+	lit, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("expression is not *ast.FunctionLiteral. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+	if len(lit.Parameters) != 2 {
+		t.Errorf("wrong number of parameters. got=%d", len(lit.Parameters))
+	}
+}
+
+func TestStructParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`struct Point { x, y }`, "struct Point { x, y }"},
+		{`struct Empty {}`, "struct Empty {}"},
+		{`impl Point { norm() { self.x * self.x } scale(k) { Point(self.x * k, self.y * k) } }`,
+			"impl Point { norm() { ((self.x) * (self.x)) } scale(k) { Point(((self.x) * k), ((self.y) * k)) } }"},
+		{`p.x + p.y`, "((p.x) + (p.y))"},
+		{`a.b.c(1)[0]`, "(((a.b).c)(1)[0])"},
+		{`-p.x`, "(-(p.x))"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	for _, input := range []string{`struct Point { x, x }`, `struct { x }`, `impl Point { x }`} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected a parse error for %s", input)
+		}
+	}
+}
+
+func TestImportExportParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "lib/strings.monkey" as s`, `import "lib/strings.monkey" as s;`},
+		{`export let x = 1;`, "export let x = 1;"},
+		{`export struct P { x }`, "export struct P { x }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	for _, input := range []string{`import "x"`, `import x as y`, `export 1`, `if (true) { export let x = 1; }`} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected a parse error for %s", input)
+		}
+	}
+}
+
+func TestMacroLiteralParsing(t *testing.T) {
+	input := `macro(x, y) { x + y; }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d", 1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("statement is not ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	macro, ok := stmt.Expression.(*ast.MacroLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.MacroLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("macro literal parameters wrong. want 2, got=%d", len(macro.Parameters))
+	}
+
+	testLiteralExpression(t, macro.Parameters[0], "x")
+	testLiteralExpression(t, macro.Parameters[1], "y")
+
+	if macro.String() != "macro(x, y) (x + y)" {
+		t.Errorf("macro.String() wrong. got=%q", macro.String())
+	}
+}
+
 func TestFunctionLiteralParsing(t *testing.T) {
 	input := `fn(x, y) { x + y }`
 
@@ -277,6 +606,16 @@ func TestFunctionLiteralParsing(t *testing.T) {
 
 }
 
+func TestFunctionLiteralWithoutBody(t *testing.T) {
+	p := New(lexer.New(`fn(x) x`))
+	p.ParseProgram()
+
+	expected := "expected next token to be '{', got 'IDENT' instead"
+	if len(p.Errors()) == 0 || p.Errors()[0] != expected {
+		t.Errorf("expected error %q, got=%q", expected, p.Errors())
+	}
+}
+
 func TestIfExpression(t *testing.T) {
 	input := `if (x < y) { x }`
 
@@ -446,6 +785,35 @@ func TestIntegerLiteralExpression(t *testing.T) {
 
 }
 
+func TestDestructuringParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let [a, b, ...rest] = arr;`, "let [a, b, ...rest] = arr;"},
+		{`let {name, age: years} = person;`, "let {name: name, age: years} = person;"},
+		{`let {"point": [x, y]} = h;`, "let {point: [x, y]} = h;"},
+		{`fn([a, b], {name}, c) { a }`, "fn([a, b], {name: name}, c)a"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	program := New(lexer.New(`fn(x, [a, b]) { a }`)).ParseProgram()
+	lit := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if len(lit.Patterns) != 2 || lit.Patterns[0] != nil || lit.Patterns[1] == nil {
+		t.Errorf("wrong parameter patterns. got=%v", lit.Patterns)
+	}
+}
+
 func TestLetStatements(t *testing.T) {
 	tests := []struct {
 		input              string
@@ -558,6 +926,9 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"5 > 5;", 5, ">", 5},
 		{"5 == 5;", 5, "==", 5},
 		{"5 != 5;", 5, "!=", 5},
+		{"5 <= 5;", 5, "<=", 5},
+		{"5 >= 5;", 5, ">=", 5},
+		{"a in b;", "a", "in", "b"},
 		{"true == true", true, "==", true},
 		{"true != false", true, "!=", false},
 		{"false == false", false, "==", false},
@@ -656,6 +1027,22 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"3 + 4 * 5 == 3 * 1 + 4 * 5",
 			"((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))",
 		},
+		{
+			"a + b <= c * d == true",
+			"(((a + b) <= (c * d)) == true)",
+		},
+		{
+			"0..n + 1 step 2",
+			"(0..(n + 1) step 2)",
+		},
+		{
+			"x in a..<b",
+			"(x in (a..<b))",
+		},
+		{
+			"a + b in c == false",
+			"(((a + b) in c) == false)",
+		},
 		{
 			"true",
 			"true",
@@ -712,6 +1099,70 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"a == b ? c + 1 : d",
+			"((a == b) ? (c + 1) : d)",
+		},
+		{
+			"a ? b : c ? d : e",
+			"(a ? b : (c ? d : e))",
+		},
+		{
+			"a ?? b == c",
+			"(a ?? (b == c))",
+		},
+		{
+			"a ?? b ?? c",
+			"((a ?? b) ?? c)",
+		},
+		{
+			"a?.b?[c] + 1",
+			"(((a?.b)?[c]) + 1)",
+		},
+		{
+			"x ? [1] : h?.k ?? 0",
+			"(x ? [1] : ((h?.k) ?? 0))",
+		},
+		{
+			"c ?[1] : [2]",
+			"(c ? [1] : [2])",
+		},
+		{
+			"a + c ?[1] : [2]",
+			"((a + c) ? [1] : [2])",
+		},
+		{
+			"c ?[xs[0]] : [h?[k]]",
+			"(c ? [(xs[0])] : [(h?[k])])",
+		},
+		{
+			"x ? h?[k] : y",
+			"(x ? (h?[k]) : y)",
+		},
+		{
+			"xs[a?[0]:]",
+			"(xs[(a?[0]):])",
+		},
+		{
+			"{h?[k]: c ?[1] : [2]}",
+			"{(h?[k]):(c ? [1] : [2])}",
+		},
+		{
+			"xs |> map(f) |> sum",
+			"((xs |> map(f)) |> sum)",
+		},
+		{
+			"a + 1 |> f >> g",
+			"((a + 1) |> (f >> g))",
+		},
+		{
+			"f >> g >> h",
+			"((f >> g) >> h)",
+		},
+		{
+			"xs |> get(k) ?? 0",
+			"((xs |> get(k)) ?? 0)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -726,3 +1177,29 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		}
 	}
 }
+
+func TestStatementsWithoutSemicolon(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 5", "let x = 5;"},
+		{"return x", "return x;"},
+		{"let x = 5\nlet y = x", "let x = 5;let y = x;"},
+		{"fn(x) { let y = x\nreturn y }", "fn(x)let y = x;return y;"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	// An unterminated block ends at EOF instead of looping forever.
+	p := New(lexer.New("fn(x) { let y = x"))
+	p.ParseProgram()
+}
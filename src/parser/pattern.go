@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+const wildcard = "_"
+
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.IDENT:
+		return p.parseIdentifierPattern()
+	case token.INT, token.STRING, token.TRUE, token.FALSE:
+		return &ast.LiteralPattern{Token: p.curToken, Value: p.parseExpression(PREFIX)}
+	case token.MINUS:
+		if !p.peekTokenIs(token.INT) {
+			p.notExpectedToken(token.INT, p.peekToken.Type)
+			return nil
+		}
+		return &ast.LiteralPattern{Token: p.curToken, Value: p.parsePrefixExpression()}
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.LBRACE:
+		return p.parseHashPattern()
+	default:
+		p.errors = append(p.errors, fmt.Sprintf("unexpected %s in pattern", p.curToken.Type))
+		return nil
+	}
+}
+
+func (p *Parser) parseIdentifierPattern() ast.Pattern {
+	tok := p.curToken
+
+	var name *ast.Identifier
+	if tok.Literal != wildcard {
+		name = &ast.Identifier{Token: tok, Value: tok.Literal}
+	}
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			p.notExpectedToken(token.IDENT, p.peekToken.Type)
+			return nil
+		}
+		return &ast.TypePattern{Token: tok, Name: name, TypeName: p.curToken.Literal}
+	}
+
+	if name == nil {
+		return &ast.WildcardPattern{Token: tok}
+	}
+	return &ast.BindingPattern{Token: tok, Name: name}
+}
+
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				p.notExpectedToken(token.IDENT, p.peekToken.Type)
+				return nil
+			}
+			pattern.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+			if !p.peekTokenIs(token.RBRACKET) {
+				p.errors = append(p.errors, "rest pattern must be the last element")
+				return nil
+			}
+			break
+		}
+
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			p.notExpectedToken(token.COMMA, p.peekToken.Type)
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		p.notExpectedToken(token.RBRACKET, p.peekToken.Type)
+		return nil
+	}
+
+	return pattern
+}
+
+// parseHashPattern parses {"key": pattern, ...}. A bare identifier key is
+// shorthand for its name as a string, so {name, age: years} binds name to
+// h["name"] and years to h["age"].
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		var key ast.Expression
+		switch p.curToken.Type {
+		case token.IDENT:
+			key = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+		case token.STRING, token.INT, token.TRUE, token.FALSE:
+			key = p.parseExpression(PREFIX)
+		default:
+			p.errors = append(p.errors, fmt.Sprintf("unexpected %s in hash pattern key", p.curToken.Type))
+			return nil
+		}
+
+		var value ast.Pattern
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			p.nextToken()
+			value = p.parsePattern()
+		} else if p.curTokenIs(token.IDENT) {
+			name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			value = &ast.BindingPattern{Token: p.curToken, Name: name}
+		} else {
+			p.notExpectedToken(token.COLON, p.peekToken.Type)
+			return nil
+		}
+		if value == nil {
+			return nil
+		}
+
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			p.notExpectedToken(token.COMMA, p.peekToken.Type)
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		p.notExpectedToken(token.RBRACE, p.peekToken.Type)
+		return nil
+	}
+
+	return pattern
+}
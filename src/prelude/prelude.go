@@ -0,0 +1,46 @@
+// Package prelude holds the standard library written in Monkey itself. Its
+// sources are embedded in the binary and evaluated into the interpreter's
+// root environment before any user code runs.
+package prelude
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/evaluator"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
+)
+
+//go:embed *.monkey
+var sources embed.FS
+
+// Load evaluates every prelude file into env in file name order.
+func Load(env *object.Environment) error {
+	names, err := fs.Glob(sources, "*.monkey")
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		source, err := sources.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		p := parser.New(lexer.New(string(source)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			return fmt.Errorf("prelude %s: %s", name, strings.Join(p.Errors(), "; "))
+		}
+
+		if result := evaluator.Eval(program, env); result != nil && result.Type() == object.ERROR_OBJ {
+			return fmt.Errorf("prelude %s: %s", name, result.Inspect())
+		}
+	}
+
+	return nil
+}
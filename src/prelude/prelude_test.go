@@ -0,0 +1,90 @@
+package prelude
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/evaluator"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
+)
+
+// TestExamples runs the examples in the prelude's comments, written as
+// indented `//   expression => result` lines. They must hold with and
+// without strict indexing, since the prelude cannot know which one a
+// program uses.
+func TestExamples(t *testing.T) {
+	testExamples(t, false)
+	testExamples(t, true)
+}
+
+func testExamples(t *testing.T, strict bool) {
+	env := object.NewEnvironment()
+	env.SetStrictIndexing(strict)
+	if err := Load(env); err != nil {
+		t.Fatalf("Load failed: %s", err)
+	}
+
+	names, err := fs.Glob(sources, "*.monkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	examples := 0
+	for _, name := range names {
+		source, err := sources.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, line := range strings.Split(string(source), "\n") {
+			if !strings.HasPrefix(line, "//   ") {
+				continue
+			}
+			example := strings.TrimPrefix(line, "//   ")
+			sep := strings.LastIndex(example, " => ")
+			if sep < 0 {
+				t.Errorf("%s: malformed example %q", name, example)
+				continue
+			}
+			input, expected := example[:sep], example[sep+len(" => "):]
+			examples++
+
+			p := parser.New(lexer.New(input))
+			program := p.ParseProgram()
+			if len(p.Errors()) != 0 {
+				t.Errorf("%s: parsing %q failed: %v", name, input, p.Errors())
+				continue
+			}
+
+			evaluated := evaluator.Eval(program, object.NewEnclosedEnvironment(env))
+			if evaluated.Inspect() != expected {
+				t.Errorf("%s: %s (strict=%t). expected=%q, got=%q", name, input, strict, expected, evaluated.Inspect())
+			}
+		}
+	}
+
+	if examples == 0 {
+		t.Errorf("no examples found in the prelude")
+	}
+}
+
+func TestLoadDefinesEveryFunction(t *testing.T) {
+	env := object.NewEnvironment()
+	if err := Load(env); err != nil {
+		t.Fatalf("Load failed: %s", err)
+	}
+
+	for _, name := range []string{"sum", "product", "max", "min", "last", "partition", "groupBy", "compose", "times"} {
+		obj, ok := env.Get(name)
+		if !ok {
+			t.Errorf("prelude does not define %s", name)
+			continue
+		}
+		if obj.Type() != object.FUNCTION_OBJ {
+			t.Errorf("%s is not a function. got=%s", name, obj.Type())
+		}
+	}
+}
@@ -0,0 +1,784 @@
+// Package printer re-emits Monkey programs in canonical style: tab
+// indentation, one statement per line, only the parentheses the parser
+// needs, and every comment of the source kept in place.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/ast"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/token"
+)
+
+// atom is the precedence of expressions that never need parentheses, such
+// as literals, calls and index expressions.
+const atom = parser.INDEX + 1
+
+// Format parses src and returns it in canonical style.
+func Format(src []byte) ([]byte, error) {
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, fmt.Errorf("%s", strings.Join(p.Errors(), "\n"))
+	}
+
+	var out bytes.Buffer
+	if err := fprint(&out, program, blankLines(src)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// Fprint writes program to w in canonical style. Comments are taken from
+// program.Comments, so a program built without the parser prints without
+// any. Unlike Format, Fprint has no source to keep blank lines from.
+func Fprint(w io.Writer, program *ast.Program) error {
+	return fprint(w, program, nil)
+}
+
+func fprint(w io.Writer, program *ast.Program, blank map[int]bool) error {
+	p := &printer{comments: program.Comments, blank: blank, atOpen: true}
+
+	p.statementList(program.Statements, true)
+	for len(p.comments) > 0 {
+		p.comment()
+	}
+	if p.out.Len() > 0 {
+		p.out.WriteString("\n")
+	}
+
+	_, err := w.Write(p.out.Bytes())
+	return err
+}
+
+type printer struct {
+	out    bytes.Buffer
+	indent int
+
+	// comments holds the comments not printed yet, in source order.
+	comments []token.Token
+	// blank holds the numbers of the empty lines of the source.
+	blank map[int]bool
+	// lastLine is the last source line printed so far; it decides where
+	// blank lines are kept and which comments trail a statement.
+	lastLine int
+	// atOpen is set at the start of the output and after an opening
+	// brace, where blank lines are dropped.
+	atOpen bool
+	// inline is set while trying to fit a block on one line; comments are
+	// left for the enclosing statement then.
+	inline bool
+}
+
+func (p *printer) print(s ...string) {
+	for _, str := range s {
+		p.out.WriteString(str)
+	}
+}
+
+// see records that the node at pos has been printed.
+func (p *printer) see(pos token.Position) {
+	if pos.Line > p.lastLine {
+		p.lastLine = pos.Line
+	}
+}
+
+// lineFor starts a new line for the node at pos, keeping one blank line if
+// the source had any since the last line printed.
+func (p *printer) lineFor(pos token.Position) {
+	if p.out.Len() > 0 {
+		if !p.atOpen && p.blankBefore(pos) {
+			p.out.WriteString("\n")
+		}
+		p.out.WriteString("\n")
+		p.out.WriteString(strings.Repeat("\t", p.indent))
+	}
+	p.atOpen = false
+	p.see(pos)
+}
+
+func (p *printer) blankBefore(pos token.Position) bool {
+	if !pos.IsValid() || p.lastLine == 0 {
+		return false
+	}
+	for line := p.lastLine + 1; line < pos.Line; line++ {
+		if p.blank[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// blankLines returns the numbers of the lines of src holding nothing but
+// white space.
+func blankLines(src []byte) map[int]bool {
+	blank := make(map[int]bool)
+	for i, line := range strings.Split(string(src), "\n") {
+		if strings.TrimSpace(line) == "" {
+			blank[i+1] = true
+		}
+	}
+	return blank
+}
+
+// comment prints the next comment on a line of its own.
+func (p *printer) comment() {
+	c := p.comments[0]
+	p.comments = p.comments[1:]
+
+	p.lineFor(c.Pos)
+	p.print(c.Literal)
+}
+
+// commentsBefore prints the comments on lines before pos.
+func (p *printer) commentsBefore(pos token.Position) {
+	if p.inline || !pos.IsValid() {
+		return
+	}
+	for len(p.comments) > 0 && p.comments[0].Pos.Line < pos.Line {
+		p.comment()
+	}
+}
+
+// commentBefore reports whether a comment not printed yet comes before
+// pos, i.e. whether the construct closed at pos has one inside it.
+func (p *printer) commentBefore(pos token.Position) bool {
+	return !p.inline && pos.IsValid() && len(p.comments) > 0 && p.comments[0].Pos.Line < pos.Line
+}
+
+// trailingComment prints the comment on the last printed line, if any,
+// after what is already there.
+func (p *printer) trailingComment() {
+	if p.inline || len(p.comments) == 0 || p.lastLine == 0 || p.comments[0].Pos.Line != p.lastLine {
+		return
+	}
+	p.print(" ", p.comments[0].Literal)
+	p.comments = p.comments[1:]
+}
+
+// statementList prints one statement per line. Expression statements get a
+// semicolon unless they are the value of their block, or end with a brace
+// and the next statement cannot be mistaken for their continuation.
+func (p *printer) statementList(list []ast.Statement, topLevel bool) {
+	for i, stmt := range list {
+		pos := ast.Pos(stmt)
+		p.commentsBefore(pos)
+		p.lineFor(pos)
+
+		last := i == len(list)-1
+		semicolon := topLevel || !last
+		if es, ok := stmt.(*ast.ExpressionStatement); ok && endsWithBrace(es.Expression) {
+			if last || !continues(list[i+1]) {
+				semicolon = false
+			}
+		}
+
+		p.statement(stmt, semicolon)
+		p.trailingComment()
+	}
+}
+
+func endsWithBrace(exp ast.Expression) bool {
+	switch exp.(type) {
+	case *ast.IfExpression, *ast.MatchExpression, *ast.TryExpression:
+		return true
+	}
+	return false
+}
+
+// continues reports whether stmt starts with a token the parser would read
+// as an operator applied to the previous expression.
+func continues(stmt ast.Statement) bool {
+	es, ok := stmt.(*ast.ExpressionStatement)
+	return ok && parser.Precedence(es.Token.Type) != parser.LOWEST
+}
+
+func (p *printer) statement(stmt ast.Statement, semicolon bool) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		p.print("let ")
+		if stmt.Pattern != nil {
+			p.pattern(stmt.Pattern)
+		} else {
+			p.expression(stmt.Name)
+		}
+		p.print(" = ")
+		p.expression(stmt.Value)
+		p.print(";")
+
+	case *ast.ReturnStatement:
+		p.print("return")
+		if stmt.ReturnValue != nil {
+			p.print(" ")
+			p.expression(stmt.ReturnValue)
+		}
+		p.print(";")
+
+	case *ast.ThrowStatement:
+		p.print("throw ")
+		p.expression(stmt.Value)
+		p.print(";")
+
+	case *ast.ExpressionStatement:
+		p.expression(stmt.Expression)
+		if semicolon {
+			p.print(";")
+		}
+
+	case *ast.StructStatement:
+		p.print("struct ", stmt.Name.Value, " {")
+		if len(stmt.Fields) > 0 {
+			fields := make([]string, len(stmt.Fields))
+			for i, f := range stmt.Fields {
+				fields[i] = f.Value
+			}
+			p.print(" ", strings.Join(fields, ", "), " ")
+		}
+		p.print("}")
+
+	case *ast.ImplStatement:
+		p.implStatement(stmt)
+
+	case *ast.ImportStatement:
+		p.print("import \"", stmt.Path.Value, "\" as ", stmt.Alias.Value, ";")
+
+	case *ast.ExportStatement:
+		p.print("export ")
+		p.statement(stmt.Statement, semicolon)
+
+	case *ast.BlockStatement:
+		p.block(stmt)
+
+	default:
+		panic(fmt.Sprintf("printer: unexpected statement %T", stmt))
+	}
+}
+
+func (p *printer) implStatement(stmt *ast.ImplStatement) {
+	p.print("impl ", stmt.Name.Value, " {")
+	if len(stmt.Methods) == 0 {
+		p.print("}")
+		return
+	}
+
+	p.indent++
+	p.atOpen = true
+	for _, m := range stmt.Methods {
+		pos := ast.Pos(m.Name)
+		p.commentsBefore(pos)
+		p.lineFor(pos)
+		p.print(m.Name.Value)
+		p.parameters(m.Function)
+		p.print(" ")
+		p.block(m.Function.Body)
+		p.trailingComment()
+	}
+	p.indent--
+
+	p.lineFor(token.Position{})
+	p.print("}")
+}
+
+// block prints a braced block. Blocks written on one line with at most one
+// statement stay on one line.
+func (p *printer) block(b *ast.BlockStatement) {
+	if b.Rbrace.IsValid() && b.Rbrace.Line == b.Token.Pos.Line && len(b.Statements) <= 1 {
+		if len(b.Statements) == 0 {
+			p.print("{}")
+			p.see(b.Rbrace)
+			return
+		}
+
+		sub := &printer{indent: p.indent, lastLine: p.lastLine, inline: true}
+		sub.statement(b.Statements[0], false)
+		if line := sub.out.String(); !strings.Contains(line, "\n") {
+			p.print("{ ", line, " }")
+			p.see(b.Rbrace)
+			return
+		}
+	}
+
+	if len(b.Statements) == 0 && (len(p.comments) == 0 || !b.Rbrace.IsValid() || p.comments[0].Pos.Line >= b.Rbrace.Line) {
+		p.print("{}")
+		p.see(b.Rbrace)
+		return
+	}
+
+	p.print("{")
+	p.indent++
+	p.atOpen = true
+	p.statementList(b.Statements, false)
+	p.commentsBefore(b.Rbrace)
+	p.indent--
+
+	p.lineFor(token.Position{})
+	p.print("}")
+	p.see(b.Rbrace)
+}
+
+// body prints the body of an arrow function or match arm, which is either
+// a block or a single expression written without braces.
+func (p *printer) body(b *ast.BlockStatement) {
+	if b.Token.Type != token.ARROW || len(b.Statements) != 1 {
+		p.block(b)
+		return
+	}
+
+	es, ok := b.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		p.block(b)
+		return
+	}
+
+	// A body starting with a brace would be read as a block.
+	_, isHash := es.Expression.(*ast.HashLiteral)
+	p.operand(es.Expression, isHash)
+}
+
+// precedence returns how tightly exp holds together when printed without
+// parentheses: the precedence of its top-level operator, or LOWEST for
+// expressions whose last operand extends as far right as possible.
+func precedence(exp ast.Expression) int {
+	switch exp := exp.(type) {
+	case *ast.InfixExpression:
+		t := token.TokenType(exp.Operator)
+		if keyword, ok := token.Keywords[exp.Operator]; ok {
+			t = keyword
+		}
+		return parser.Precedence(t)
+	case *ast.RangeExpression:
+		return parser.RANGE
+	case *ast.PrefixExpression:
+		return parser.PREFIX
+	case *ast.ConditionalExpression:
+		return parser.LOWEST
+	case *ast.FunctionLiteral:
+		if exp.IsArrow {
+			return parser.LOWEST
+		}
+	case *ast.YieldExpression:
+		if exp.Value != nil {
+			return parser.LOWEST
+		}
+	}
+	return atom
+}
+
+// left prints the left operand of an operator of precedence prec. Operators
+// group to the left, so an operand of the same precedence stays bare.
+func (p *printer) left(exp ast.Expression, prec int) {
+	p.operand(exp, precedence(exp) < prec)
+}
+
+// right prints an operand parsed with parseExpression(prec).
+func (p *printer) right(exp ast.Expression, prec int) {
+	p.operand(exp, precedence(exp) <= prec)
+}
+
+func (p *printer) operand(exp ast.Expression, parens bool) {
+	if parens {
+		p.print("(")
+		p.expression(exp)
+		p.print(")")
+		return
+	}
+	p.expression(exp)
+}
+
+func (p *printer) expressionList(list []ast.Expression) {
+	for i, exp := range list {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.expression(exp)
+	}
+}
+
+// bracketedList prints the elements of an array literal or the arguments
+// of a call, followed by the closing bracket at end. A list with a comment
+// inside is printed one element per line, so the comment stays in place.
+func (p *printer) bracketedList(list []ast.Expression, closing string, end token.Position) {
+	if !p.commentBefore(end) {
+		p.expressionList(list)
+		p.print(closing)
+		p.see(end)
+		return
+	}
+
+	p.indent++
+	p.atOpen = true
+	for i, exp := range list {
+		pos := ast.Pos(exp)
+		p.commentsBefore(pos)
+		p.lineFor(pos)
+		p.expression(exp)
+		if i < len(list)-1 {
+			p.print(",")
+		}
+		p.trailingComment()
+	}
+	p.commentsBefore(end)
+	p.indent--
+
+	p.lineFor(token.Position{})
+	p.print(closing)
+	p.see(end)
+}
+
+func (p *printer) expression(exp ast.Expression) {
+	p.see(ast.Pos(exp))
+
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		p.print(exp.Value)
+
+	case *ast.IntegerLiteral:
+		if exp.Token.Literal != "" {
+			p.print(exp.Token.Literal)
+		} else {
+			p.print(strconv.FormatInt(exp.Value, 10))
+		}
+
+	case *ast.StringLiteral:
+		p.print(`"`, exp.Value, `"`)
+
+	case *ast.Boolean:
+		p.print(strconv.FormatBool(exp.Value))
+
+	case *ast.ArrayLiteral:
+		p.print("[")
+		p.bracketedList(exp.Elements, "]", exp.Rbracket)
+
+	case *ast.HashLiteral:
+		p.hashLiteral(exp)
+
+	case *ast.PrefixExpression:
+		// -(-x) rather than --x, which reads like a decrement.
+		inner, isPrefix := exp.Right.(*ast.PrefixExpression)
+		p.print(exp.Operator)
+		p.operand(exp.Right, precedence(exp.Right) < parser.PREFIX || isPrefix && exp.Operator == "-" && inner.Operator == "-")
+
+	case *ast.InfixExpression:
+		prec := precedence(exp)
+		p.left(exp.Left, prec)
+		p.print(" ", exp.Operator, " ")
+		p.right(exp.Right, prec)
+
+	case *ast.RangeExpression:
+		p.left(exp.Start, parser.RANGE)
+		if exp.Inclusive {
+			p.print("..")
+		} else {
+			p.print("..<")
+		}
+		p.right(exp.End, parser.RANGE)
+		if exp.Step != nil {
+			p.print(" step ")
+			p.right(exp.Step, parser.RANGE)
+		}
+
+	case *ast.ConditionalExpression:
+		p.left(exp.Condition, parser.TERNARY+1)
+		p.print(" ? ")
+		p.expression(exp.Consequence)
+		p.print(" : ")
+		p.expression(exp.Alternative)
+
+	case *ast.IfExpression:
+		p.print("if (")
+		p.expression(exp.Condition)
+		p.print(") ")
+		p.block(exp.Consequence)
+		if exp.Alternative != nil {
+			p.print(" else ")
+			if elseIf := elseIfExpression(exp.Alternative); elseIf != nil {
+				p.expression(elseIf)
+			} else {
+				p.block(exp.Alternative)
+			}
+		}
+
+	case *ast.FunctionLiteral:
+		p.functionLiteral(exp)
+
+	case *ast.MacroLiteral:
+		p.print("macro(")
+		p.expressionList(identifiers(exp.Parameters))
+		p.print(") ")
+		p.block(exp.Body)
+
+	case *ast.CallExpression:
+		p.left(exp.Function, parser.CALL)
+		p.print("(")
+		p.bracketedList(exp.Arguments, ")", exp.Rparen)
+
+	case *ast.IndexExpression:
+		p.left(exp.Left, parser.INDEX)
+		p.print("[")
+		p.expression(exp.Index)
+		p.print("]")
+
+	case *ast.SliceExpression:
+		p.left(exp.Left, parser.INDEX)
+		p.print("[")
+		if exp.Start != nil {
+			p.expression(exp.Start)
+		}
+		p.print(":")
+		if exp.End != nil {
+			p.expression(exp.End)
+		}
+		p.print("]")
+
+	case *ast.OptionalIndexExpression:
+		p.left(exp.Left, parser.INDEX)
+		if exp.Token.Type == token.OPTIONAL_DOT {
+			p.print("?.", exp.Index.(*ast.StringLiteral).Value)
+		} else {
+			p.print("?[")
+			p.expression(exp.Index)
+			p.print("]")
+		}
+
+	case *ast.MemberExpression:
+		p.left(exp.Left, parser.INDEX)
+		p.print(".", exp.Member.Value)
+
+	case *ast.YieldExpression:
+		p.print("yield")
+		if exp.Value != nil {
+			p.print(" ")
+			p.expression(exp.Value)
+		}
+
+	case *ast.TryExpression:
+		p.print("try ")
+		p.block(exp.Block)
+		if exp.Catch != nil {
+			p.print(" catch ")
+			if exp.CatchParam != nil {
+				p.print("(", exp.CatchParam.Value, ") ")
+			}
+			p.block(exp.Catch)
+		}
+		if exp.Finally != nil {
+			p.print(" finally ")
+			p.block(exp.Finally)
+		}
+
+	case *ast.MatchExpression:
+		p.matchExpression(exp)
+
+	default:
+		panic(fmt.Sprintf("printer: unexpected expression %T", exp))
+	}
+}
+
+// elseIfExpression returns the if expression of an else-if branch, which
+// the parser wraps in a block without braces.
+func elseIfExpression(b *ast.BlockStatement) *ast.IfExpression {
+	if b.Token.Type != token.IF || len(b.Statements) != 1 {
+		return nil
+	}
+	if es, ok := b.Statements[0].(*ast.ExpressionStatement); ok {
+		if ie, ok := es.Expression.(*ast.IfExpression); ok {
+			return ie
+		}
+	}
+	return nil
+}
+
+func identifiers(list []*ast.Identifier) []ast.Expression {
+	exps := make([]ast.Expression, len(list))
+	for i, ident := range list {
+		exps[i] = ident
+	}
+	return exps
+}
+
+func (p *printer) functionLiteral(fl *ast.FunctionLiteral) {
+	if !fl.IsArrow {
+		p.print("fn")
+		if fl.IsGenerator {
+			p.print("*")
+		}
+		p.parameters(fl)
+		p.print(" ")
+		p.block(fl.Body)
+		return
+	}
+
+	if len(fl.Parameters) == 1 && len(fl.Patterns) == 0 {
+		p.print(fl.Parameters[0].Value)
+	} else {
+		p.parameters(fl)
+	}
+	p.print(" => ")
+	p.body(fl.Body)
+}
+
+func (p *printer) parameters(fl *ast.FunctionLiteral) {
+	p.print("(")
+	for i, param := range fl.Parameters {
+		if i > 0 {
+			p.print(", ")
+		}
+		if i < len(fl.Patterns) && fl.Patterns[i] != nil {
+			p.pattern(fl.Patterns[i])
+		} else {
+			p.print(param.Value)
+		}
+	}
+	p.print(")")
+}
+
+// hashLiteral prints the pairs in source order, one per line if the source
+// started them on a new line or there is a comment between them.
+func (p *printer) hashLiteral(hl *ast.HashLiteral) {
+	keys := hl.Keys()
+	if len(keys) == 0 {
+		p.print("{}")
+		return
+	}
+
+	if first := ast.Pos(keys[0]); !first.IsValid() || first.Line == hl.Token.Pos.Line && !p.commentBefore(hl.Rbrace) {
+		p.print("{")
+		for i, key := range keys {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.expression(key)
+			p.print(": ")
+			p.expression(hl.Pairs[key])
+		}
+		p.print("}")
+		return
+	}
+
+	p.print("{")
+	p.indent++
+	p.atOpen = true
+	for _, key := range keys {
+		pos := ast.Pos(key)
+		p.commentsBefore(pos)
+		p.lineFor(pos)
+		p.expression(key)
+		p.print(": ")
+		p.expression(hl.Pairs[key])
+		p.print(",")
+		p.trailingComment()
+	}
+	p.commentsBefore(hl.Rbrace)
+	p.indent--
+
+	p.lineFor(token.Position{})
+	p.print("}")
+	p.see(hl.Rbrace)
+}
+
+// matchExpression prints one arm per line. Arms with an expression body
+// end with a comma; arms with a block do not need one.
+func (p *printer) matchExpression(me *ast.MatchExpression) {
+	p.print("match (")
+	p.expression(me.Value)
+	p.print(") {")
+
+	p.indent++
+	p.atOpen = true
+	for _, arm := range me.Arms {
+		pos := ast.Pos(arm.Pattern)
+		p.commentsBefore(pos)
+		p.lineFor(pos)
+
+		p.pattern(arm.Pattern)
+		if arm.Guard != nil {
+			p.print(" if ")
+			p.expression(arm.Guard)
+		}
+		p.print(" => ")
+		p.body(arm.Body)
+		if arm.Body.Token.Type == token.ARROW {
+			p.print(",")
+		}
+		p.trailingComment()
+	}
+	p.indent--
+
+	p.lineFor(token.Position{})
+	p.print("}")
+}
+
+func (p *printer) pattern(pattern ast.Pattern) {
+	p.see(ast.Pos(pattern))
+
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		p.print("_")
+
+	case *ast.BindingPattern:
+		p.print(pattern.Name.Value)
+
+	case *ast.LiteralPattern:
+		p.expression(pattern.Value)
+
+	case *ast.TypePattern:
+		if pattern.Name != nil {
+			p.print(pattern.Name.Value)
+		} else {
+			p.print("_")
+		}
+		p.print(": ", pattern.TypeName)
+
+	case *ast.ArrayPattern:
+		p.print("[")
+		for i, el := range pattern.Elements {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.pattern(el)
+		}
+		if pattern.Rest != nil {
+			if len(pattern.Elements) > 0 {
+				p.print(", ")
+			}
+			p.print("...", pattern.Rest.Value)
+		}
+		p.print("]")
+
+	case *ast.HashPattern:
+		p.print("{")
+		for i, key := range pattern.Keys {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.hashPatternPair(key, pattern.Values[i])
+		}
+		p.print("}")
+
+	default:
+		panic(fmt.Sprintf("printer: unexpected pattern %T", pattern))
+	}
+}
+
+// hashPatternPair prints {name} for a key written as a bare identifier
+// and bound to the same name, and key: pattern otherwise.
+func (p *printer) hashPatternPair(key ast.Expression, value ast.Pattern) {
+	if str, ok := key.(*ast.StringLiteral); ok && str.Token.Type == token.IDENT {
+		if binding, ok := value.(*ast.BindingPattern); ok && binding.Name.Value == str.Value {
+			p.print(str.Value)
+			return
+		}
+		p.print(str.Value)
+	} else {
+		p.expression(key)
+	}
+
+	p.print(": ")
+	p.pattern(value)
+}
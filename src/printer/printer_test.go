@@ -0,0 +1,281 @@
+package printer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
+	"github.com/g-hyoga/writing-interpreter-in-go/src/parser"
+)
+
+func TestMinimalParentheses(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"((-a) * b)", "-a * b"},
+		{"(!(-a))", "!-a"},
+		{"-(-a)", "-(-a)"},
+		{"((a + b) + c)", "a + b + c"},
+		{"(a + (b + c))", "a + (b + c)"},
+		{"(a + (b * c))", "a + b * c"},
+		{"((a + b) * c)", "(a + b) * c"},
+		{"(((a + (b * c)) + (d / e)) - f)", "a + b * c + d / e - f"},
+		{"((5 > 4) == (3 < 4))", "5 > 4 == 3 < 4"},
+		{"(-(a + b))", "-(a + b)"},
+		{"((-a)[0])", "(-a)[0]"},
+		{"(-(a[0]))", "-a[0]"},
+		{"((a + b)(c))", "(a + b)(c)"},
+		{"(f(x)[0])", "f(x)[0]"},
+		{"((a * [1, 2, 3, 4][(b * c)]) * d)", "a * [1, 2, 3, 4][b * c] * d"},
+		{"((0..10) + 1)", "(0..10) + 1"},
+		{"(0..(10 + 1))", "0..10 + 1"},
+		{"((0..10)[2:5])", "(0..10)[2:5]"},
+		{"(a ? b : (c ? d : e))", "a ? b : c ? d : e"},
+		{"((a ? b : c) ? d : e)", "(a ? b : c) ? d : e"},
+		{"((a ?? b) ? c : d)", "a ?? b ? c : d"},
+		{"(a + (b ? c : d))", "a + (b ? c : d)"},
+		{"((xs |> f) ?? 0)", "xs |> f ?? 0"},
+		{"(xs |> (f >> g))", "xs |> f >> g"},
+		{"((x => x) >> g)", "(x => x) >> g"},
+		{"(x => (x + 1))(2)", "(x => x + 1)(2)"},
+		{"map(xs, (x => x * 2))", "map(xs, x => x * 2)"},
+		{"(x => ({\"a\": x}))", "x => ({\"a\": x})"},
+		{"((p.x) + (p?.y))", "p.x + p?.y"},
+		{"((-p).x)", "(-p).x"},
+		{"(a in (b + c))", "a in b + c"},
+	}
+
+	for _, tt := range tests {
+		formatted := format(t, tt.input)
+		if formatted != tt.expected+";\n" {
+			t.Errorf("Format(%q) wrong. expected=%q, got=%q", tt.input, tt.expected+";\n", formatted)
+		}
+		if got, want := parse(t, formatted), parse(t, tt.input); got != want {
+			t.Errorf("Format(%q) changed the meaning. expected=%q, got=%q", tt.input, want, got)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"let add=fn(x,y){x+y;};add(1,2)",
+			"let add = fn(x, y) { x + y };\nadd(1, 2);\n",
+		},
+		{
+			"let f = fn(x) {\nlet y = x * 2; y\n}",
+			"let f = fn(x) {\n\tlet y = x * 2;\n\ty\n};\n",
+		},
+		{
+			"let f = fn(x) { let y = x; y };",
+			"let f = fn(x) {\n\tlet y = x;\n\ty\n};\n",
+		},
+		{
+			"if (x) { 1 } else if (y) { 2 } else { 3 }",
+			"if (x) { 1 } else if (y) { 2 } else { 3 }\n",
+		},
+		{
+			"if (x) {\n  puts(1);\n  2\n};\n-1",
+			"if (x) {\n\tputs(1);\n\t2\n};\n-1;\n",
+		},
+		{
+			"if (x) {\n  1\n}\nlet y = 2;",
+			"if (x) {\n\t1\n}\nlet y = 2;\n",
+		},
+		{
+			"let a = 1;\n\n\n\nlet b = 2;\nlet c = 3;",
+			"let a = 1;\n\nlet b = 2;\nlet c = 3;\n",
+		},
+		{
+			"let h = {\"a\": 1, \"b\": 2};",
+			"let h = {\"a\": 1, \"b\": 2};\n",
+		},
+		{
+			"let h = {\n  \"b\": 1,\n  \"a\": 2\n};",
+			"let h = {\n\t\"b\": 1,\n\t\"a\": 2,\n};\n",
+		},
+		{
+			"match (x) { 0 => \"zero\", n: INTEGER if n > 0 => { n } [a, ...r] => a, {name, age: y} => y, _ => null }",
+			"match (x) {\n\t0 => \"zero\",\n\tn: INTEGER if n > 0 => { n }\n\t[a, ...r] => a,\n\t{name, age: y} => y,\n\t_ => null,\n}\n",
+		},
+		{
+			"struct Point {x,y}\nimpl Point { norm() { self.x } add(o) {\nPoint(self.x + o.x, self.y + o.y) } }",
+			"struct Point { x, y }\nimpl Point {\n\tnorm() { self.x }\n\tadd(o) {\n\t\tPoint(self.x + o.x, self.y + o.y)\n\t}\n}\n",
+		},
+		{
+			"import \"lib/math.monkey\" as math\nexport let [a, b] = math.pair",
+			"import \"lib/math.monkey\" as math;\nexport let [a, b] = math.pair;\n",
+		},
+		{
+			"let g = fn*() { yield 1; yield }; let t = try { f() } catch(e) { e } finally { done() }",
+			"let g = fn*() {\n\tyield 1;\n\tyield\n};\nlet t = try { f() } catch (e) { e } finally { done() };\n",
+		},
+		{
+			"let unless = macro(c, a, b) { quote(if (!(unquote(c))) { unquote(a) } else { unquote(b) }) };",
+			"let unless = macro(c, a, b) { quote(if (!unquote(c)) { unquote(a) } else { unquote(b) }) };\n",
+		},
+		{
+			"let f = fn([a, b], {name}) { a }; let g = (x, y) => { x }; let r = 1..<10 step 2",
+			"let f = fn([a, b], {name}) { a };\nlet g = (x, y) => { x };\nlet r = 1..<10 step 2;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		formatted := format(t, tt.input)
+		if formatted != tt.expected {
+			t.Errorf("Format(%q) wrong.\nexpected=%q\ngot=     %q", tt.input, tt.expected, formatted)
+		}
+		if got, want := parse(t, formatted), parse(t, tt.input); got != want {
+			t.Errorf("Format(%q) changed the meaning. expected=%q, got=%q", tt.input, want, got)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `// Package comment.
+
+
+// double doubles x.
+let double = fn(x) { x * 2 }; // trailing
+
+let f = fn(x) {
+    // inside
+    let y = x;   // after y
+
+    // before the value
+    y
+    // before the brace
+};
+let h = {
+  "a": 1, // first
+  // second
+  "b": 2
+};
+let g = {"b": [1,
+  // inner
+  2]};
+f(a, // first
+  b
+  // last
+);
+let k = {"a": 1, // one
+  "b": 2};
+match (x) {
+  // zero
+  0 => 1,
+  _ => 2 // other
+}
+// end
+`
+	expected := `// Package comment.
+
+// double doubles x.
+let double = fn(x) { x * 2 }; // trailing
+
+let f = fn(x) {
+	// inside
+	let y = x; // after y
+
+	// before the value
+	y
+	// before the brace
+};
+let h = {
+	"a": 1, // first
+	// second
+	"b": 2,
+};
+let g = {
+	"b": [
+		1,
+		// inner
+		2
+	],
+};
+f(
+	a, // first
+	b
+	// last
+);
+let k = {
+	"a": 1, // one
+	"b": 2,
+};
+match (x) {
+	// zero
+	0 => 1,
+	_ => 2, // other
+}
+// end
+`
+
+	formatted := format(t, input)
+	if formatted != expected {
+		t.Errorf("comments not kept.\nexpected=\n%s\ngot=\n%s", expected, formatted)
+	}
+}
+
+func TestFormatIsIdempotent(t *testing.T) {
+	sources := []string{
+		"let a = (1 + 2) * 3; // c\nlet f = fn(x) {\n// c\nx\n}\n\n\nf(a)",
+		"match (x) { 0 => { let y = 1; y } _ => x }",
+		"let h = {\"b\": [1,\n// inner\n2], \"c\": f(x, // arg\ny)}",
+	}
+
+	for _, source := range sources {
+		once := format(t, source)
+		if twice := format(t, once); twice != once {
+			t.Errorf("formatting is not idempotent.\nonce=\n%s\ntwice=\n%s", once, twice)
+		}
+	}
+}
+
+func TestPreludeIsFormatted(t *testing.T) {
+	paths, err := filepath.Glob("../prelude/*.monkey")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no prelude sources found: %v", err)
+	}
+
+	for _, path := range paths {
+		source, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if formatted := format(t, string(source)); formatted != string(source) {
+			t.Errorf("%s is not formatted. got=\n%s", path, formatted)
+		}
+	}
+}
+
+func TestFormatParseError(t *testing.T) {
+	if _, err := Format([]byte("let = 1;")); err == nil {
+		t.Errorf("expected an error for invalid source")
+	}
+}
+
+func format(t *testing.T, input string) string {
+	t.Helper()
+
+	formatted, err := Format([]byte(input))
+	if err != nil {
+		t.Fatalf("Format(%q) failed: %s", input, err)
+	}
+	return string(formatted)
+}
+
+// parse returns the fully parenthesized rendering of input.
+func parse(t *testing.T, input string) string {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parse(%q) failed: %v", input, p.Errors())
+	}
+	return program.String()
+}
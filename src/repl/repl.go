@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/g-hyoga/writing-interpreter-in-go/src/dot"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/evaluator"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/lexer"
 	"github.com/g-hyoga/writing-interpreter-in-go/src/object"
@@ -13,9 +14,9 @@ import (
 
 const PROMPT = ">> "
 
-func Start(in io.Reader, out io.Writer) {
+func Start(in io.Reader, out io.Writer, env *object.Environment) {
 	scanner := bufio.NewScanner(in)
-	env := object.NewEnvironment()
+	macroEnv := object.NewEnvironment()
 
 	for {
 		fmt.Printf(PROMPT)
@@ -31,6 +32,13 @@ func Start(in io.Reader, out io.Writer) {
 			return
 		}
 
+		// :env prints the current scope chain, and everything the
+		// closures in it capture, as a DOT graph for Graphviz.
+		if line == ":env" {
+			dot.Environment(out, env)
+			continue
+		}
+
 		l := lexer.New(line)
 		p := parser.New(l)
 		program := p.ParseProgram()
@@ -39,7 +47,15 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
-		evaluated := evaluator.Eval(program, env)
+		evaluator.DefineMacros(program, macroEnv)
+		expanded, err := evaluator.ExpandMacros(program, macroEnv)
+		if err != nil {
+			io.WriteString(out, err.Inspect())
+			io.WriteString(out, "\n")
+			continue
+		}
+
+		evaluated := evaluator.Eval(expanded, env)
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
@@ -1,15 +1,35 @@
 package token
 
+import "fmt"
+
 type TokenType string
 
 type Token struct {
 	Type    TokenType
 	Literal string
+	Pos     Position
+}
+
+// Position is the line and column, both starting at 1, of the first
+// character of a token. The zero Position marks tokens that were not read
+// from source, e.g. ones synthesized by the parser.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
 }
 
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
+	COMMENT = "COMMENT" // a // line comment, kept aside by the lexer
 
 	// Identifiers + literals
 	IDENT = "IDENT" // add, foobar, x, y, ...
@@ -23,12 +43,28 @@ const (
 	ASTERISK = "*"
 	SLASH    = "/"
 
-	LT = "<"
-	GT = ">"
+	LT    = "<"
+	GT    = ">"
+	LT_EQ = "<="
+	GT_EQ = ">="
 
 	EQ     = "=="
 	NOT_EQ = "!="
 
+	DOT       = "."
+	DOTDOT    = ".."
+	DOTDOT_LT = "..<"
+	ELLIPSIS  = "..."
+	ARROW     = "=>"
+
+	PIPE    = "|>"
+	COMPOSE = ">>"
+
+	QUESTION       = "?"
+	NULLISH        = "??"
+	OPTIONAL_DOT   = "?."
+	OPTIONAL_INDEX = "?["
+
 	// Delimiters
 	COMMA     = ","
 	COLON     = ":"
@@ -50,16 +86,42 @@ const (
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
 	STRING   = "STRING"
+	IN       = "IN"
+	YIELD    = "YIELD"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
+	MATCH    = "MATCH"
+	STRUCT   = "STRUCT"
+	IMPL     = "IMPL"
+	IMPORT   = "IMPORT"
+	EXPORT   = "EXPORT"
+	AS       = "AS"
+	MACRO    = "MACRO"
 )
 
 var Keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":      FUNCTION,
+	"let":     LET,
+	"true":    TRUE,
+	"false":   FALSE,
+	"if":      IF,
+	"else":    ELSE,
+	"return":  RETURN,
+	"in":      IN,
+	"yield":   YIELD,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"match":   MATCH,
+	"struct":  STRUCT,
+	"impl":    IMPL,
+	"import":  IMPORT,
+	"export":  EXPORT,
+	"as":      AS,
+	"macro":   MACRO,
+	"throw":   THROW,
 }
 
 func LookupIdent(ident string) TokenType {